@@ -56,11 +56,31 @@
 //
 // Request builder features:
 //   - HTTP methods: GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS, TRACE, CONNECT
+//   - WebDAV methods: PROPFIND, PROPPATCH, MKCOL, COPY, MOVE, LOCK, UNLOCK
 //   - Convenience methods: WithMethodGET, WithMethodPOST, WithMethodPUT, WithMethodDELETE, WithMethodPATCH, WithMethodHEAD, WithMethodOPTIONS, WithMethodTRACE, WithMethodCONNECT
+//   - WebDAV convenience methods: WithMethodPROPFIND, WithMethodPROPPATCH, WithMethodMKCOL, WithMethodCOPY, WithMethodMOVE, WithMethodLOCK, WithMethodUNLOCK
+//   - X-HTTP-Method-Override for proxies that only allow GET and POST (WithMethodOverride)
 //   - Query parameters with automatic URL encoding and validation
+//   - Array query parameters in repeat (id=1&id=2), comma (id=1,2) or brackets (id[]=1&id[]=2) format
+//   - Multi-valued query parameters from url.Values (WithQueryValues)
+//   - Query parameters that replace existing values of the key, e.g. for pagination (SetQueryParam)
+//   - Time query parameters formatted with a layout, RFC 3339 by default (WithQueryParamTime)
+//   - Query strings sent verbatim, e.g. for pre-signed URLs (WithRawQueryVerbatim)
+//   - Query encoding with spaces as "+" (form, default) or "%20" (percent)
 //   - Custom headers with format validation
-//   - Authentication: Basic Auth and Bearer Token with validation
-//   - Multiple body formats: JSON (auto-marshal), string, bytes, io.Reader
+//   - Header insertion order for signing schemes (WithHeaderOrderPreservation, HeaderOrder);
+//     net/http still decides the order on the wire
+//   - Authentication: Basic Auth, Bearer Token and custom schemes (WithAuthScheme) with validation
+//   - Layered credentials applied at Build time (WithCredentials with BasicAuth, BearerToken,
+//     APIKeyHeader or a custom Credential)
+//   - Opt-in Basic Auth from the userinfo of the base URL, like curl (WithAuthFromURL)
+//   - Bearer tokens resolved on every Build for token rotation (WithBearerAuthFunc)
+//   - HMAC-SHA256 request signing at Build time (WithHMACSignature)
+//   - Content-Digest and Content-MD5 integrity headers over the final body (WithContentDigest)
+//   - Multiple body formats: JSON (auto-marshal), gzip-compressed JSON, string, bytes, io.Reader
+//   - Inspect the exact body bytes to be sent without consuming them (BodyBytes)
+//   - Opt-in check that the body is present, well-formed JSON (WithValidateJSONBody)
+//   - Content-Type detected from the body when none is set (WithAutoDetectContentType)
 //   - Context support for timeouts and cancellation
 //   - Input validation with error accumulation
 //   - Detailed error messages indicating what failed
@@ -123,11 +143,18 @@
 // Generic client features:
 //   - Type-safe responses with automatic JSON unmarshaling
 //   - Compile-time type checking for response data
-//   - Convenience methods: Get, Post, Put, Delete, Patch
+//   - Convenience methods: Get, Post, Put, Delete, Patch, Head
 //   - Execute method for custom requests (works with RequestBuilder)
 //   - ExecuteRaw for non-JSON responses (images, files, etc.)
+//   - SubscribeSSE for server-sent event streams (text/event-stream)
+//   - StreamNDJSON for newline-delimited JSON streams, decoded incrementally
+//   - CallRPC for JSON-RPC 2.0 methods, with error objects returned as *RPCError
+//   - GetPage for paginated list responses, decoded into items, total and metadata
+//   - DownloadVerified for downloads checked against a SHA-256 checksum (ErrChecksumMismatch)
 //   - Flexible configuration via option pattern
 //   - Built-in retry logic with configurable strategies
+//   - Cumulative request, retry and attempt counters via Stats
+//   - Connection pool warmup with Warmup for fast first requests
 //   - Connection pooling and timeout configuration
 //   - TLS handshake and idle connection timeout settings
 //   - Structured error responses with ErrorResponse type
@@ -135,19 +162,79 @@
 //   - Debug logging support (uses slog)
 //
 // Configuration options:
-//   - WithTimeout: Set request timeout
+//   - WithTimeout: Set request timeout, enforced per attempt so every retry gets the full timeout
+//   - WithPerRequestTimeout: Bound each convenience-method call with a context timeout
 //   - WithMaxRetries: Set maximum retry attempts
+//   - WithMaxRetriesForStatus: Override maximum retry attempts per status code
+//   - WithNonRetryableStatusCodes: Never retry specific 5xx/429 status codes
+//   - WithRetryBudget: Limit retries across all requests of the client
 //   - WithRetryStrategy: Configure retry strategy (fixed, jitter, exponential)
+//   - WithRetryStrategyFunc: Use a custom RetryStrategy function for backoff
+//   - WithRetryableErrorFunc: Decide which transport errors are retried
+//   - WithRetryOnConnectionReset: Always retry connection resets (default true)
+//   - WithMaxResponseTime: Bound a request including all retries (ErrResponseTooSlow)
+//   - WithAbsoluteMaxDelay: Hard ceiling on every retry delay, including Retry-After
+//   - WithRetryAfterMaxWait: Give up instead of honoring a longer Retry-After (ErrRetryAfterTooLong)
+//   - WithRespectRetryAfter: Wait as long as the Retry-After header asks (default false)
+//   - WithPerAttemptTimeout: Bound each attempt with a fresh timeout (ErrAttemptTimeout)
+//   - WithPhaseTimeouts: Set connect, TLS handshake, write, response header, body read, attempt and overall timeouts
+//   - WithResponseHeaderTimeout: Bound the wait for the response headers
+//   - WithBodyReadTimeout: Bound each read of the response body (ErrBodyReadTimeout)
+//   - WithMaxRetryBodyBytes: Buffer small streaming request bodies so they can be retried
 //   - WithRetryBaseDelay: Set base delay for retry strategies
 //   - WithRetryMaxDelay: Set maximum delay for retry strategies
+//   - WithRetryMultiplier: Set the growth factor of exponential backoff (default 2)
 //   - WithMaxIdleConns: Set maximum idle connections
 //   - WithIdleConnTimeout: Set idle connection timeout
 //   - WithTLSHandshakeTimeout: Set TLS handshake timeout
 //   - WithExpectContinueTimeout: Set expect continue timeout
 //   - WithMaxIdleConnsPerHost: Set maximum idle connections per host
 //   - WithDisableKeepAlive: Disable HTTP keep-alive
+//   - WithDisableCompression: Disable transparent gzip compression of the transport
 //   - WithProxy: Configure HTTP/HTTPS proxy server
+//   - WithProxyConnectHeader: Send extra headers on proxy CONNECT requests
 //   - WithHTTPClient: Use a pre-configured HTTP client (takes precedence)
+//   - WithTransport: Set the transport of the built client, keeping timeout and retries
+//   - WithTLSServerName: Override the TLS ServerName (SNI) sent to the server
+//   - WithMinTLSVersion: Enforce a minimum TLS version (e.g. tls.VersionTLS12)
+//   - WithDNSCache: Cache resolved host addresses to skip repeated DNS lookups
+//   - WithMaxConnLifetime: Replace pooled connections older than a maximum age once idle
+//   - WithMaxHeaderBytes: Reject responses with oversized headers
+//   - WithLocalAddr: Bind outgoing connections to a local address (source IP)
+//   - WithUnixSocket: Send requests over a unix domain socket (e.g. a local daemon API)
+//   - WithDeadlinePropagationHeader: Send the remaining context deadline in a request header
+//   - WithAttemptsHeader: Set a response header to the number of attempts of the request
+//   - WithContextValue: Attach a value to every request context for middleware
+//   - WithBaseQueryParam / WithBaseQueryParams: Add query parameters to every request
+//   - WithRequestEditor: Modify every request just before it is sent
+//   - WithRequestValidator: Reject requests that violate a policy before they are sent
+//   - WithGzipResponses: Request gzip responses and decompress them before decoding
+//   - WithPreflight: Probe the endpoint before uploads and skip them if it is down
+//   - WithResponseHeaderValidator: Check response headers before decoding the body
+//   - WithRawBodyHook: Inspect the raw bytes of every response body before decoding
+//   - WithAllowEmptyBody: Fail 2xx responses without a body with ErrEmptyBody when false
+//   - WithRejectJSONNulls: Fail with ErrJSONNull when a non-nullable field of T is null
+//   - WithResponseSchema: Validate bodies against a JSON Schema subset, failing with ErrSchemaViolation
+//   - WithAfterResponse: Run a callback with status, elapsed time and error after every call
+//   - WithFallback: Return a fallback response, e.g. cached data, when a request fails
+//   - WithProgress: Report the progress of reading response bodies, e.g. for downloads
+//   - WithDebugDump: Write the full HTTP exchange in wire format to an io.Writer
+//   - WithMaxErrorBodyBytes: Limit bytes read from error response bodies (default 64 KB)
+//   - WithStatusMessages: Friendly error messages per status code when the body has none
+//   - WithDecoder / WithDecoderCtx: Decode response bodies with a custom function (e.g. XML)
+//   - WithBufferPool: Read response bodies into pooled buffers (RawBody is not set)
+//   - WithAssumedCharset: Transcode bodies without a declared charset, e.g. Latin-1, to UTF-8
+//   - WithExpectedContentType: Require a response media type before decoding
+//   - WithErrorType: Decode error bodies into a custom type returned via ResponseError
+//   - WithErrorDecoder: Build the errors of error responses from status, body and headers
+//   - WithIdleConnPruneInterval: Periodically close idle connections (stopped by Close)
+//   - WithSSEReconnect: Reconnect interrupted SubscribeSSE streams with Last-Event-ID
+//   - WithPageFields: Set the fields GetPage reads the items and total from
+//
+// SetDefaultClientConfig sets a ClientBuilder configuration that new generic clients
+// start from, so organization-wide defaults are defined once:
+//
+//	httpx.SetDefaultClientConfig(httpx.NewClientBuilder().WithTimeout(15 * time.Second))
 //
 // Integration with RequestBuilder:
 //
@@ -193,10 +280,44 @@
 //   - HTTP 5xx server errors (500-599)
 //   - HTTP 429 (Too Many Requests)
 //
+// With WithRespectRetryAfter, a Retry-After header on 429 and 503 responses is honored
+// when it asks for a longer wait than the strategy, capped at the maximum retry delay.
+//
+// WithRequestMaxRetries sets the retry limit of a single request through its context,
+// taking precedence over the client configuration; zero means no retries:
+//
+//	ctx := httpx.WithRequestMaxRetries(context.Background(), 0)
+//	resp, err := client.Do(req.WithContext(ctx))
+//
+// A retry budget (WithRetryBudget) limits retries to a share of the requests sent
+// recently; when it is spent, failed requests return ErrRetryBudgetExhausted.
+//
+// A maximum response time (WithMaxResponseTime) bounds a request including all of its
+// retries and retry delays; requests exceeding it fail with ErrResponseTooSlow.
+//
+// A per-attempt timeout (WithPerAttemptTimeout) gives every attempt a fresh timeout;
+// attempts that time out are retried, and the last one fails with ErrAttemptTimeout.
+// The client timeout (WithTimeout) is enforced per attempt in the same way instead of
+// as an http.Client.Timeout, which would span all attempts and retry delays.
+// WithPhaseTimeouts sets the timeouts of every request phase at once, from connecting
+// to the overall bound including retries; zero fields keep the current settings.
+// A body read timeout (WithBodyReadTimeout) fails reads of a stalled response body with
+// ErrBodyReadTimeout, restarting its deadline on every read.
+//
 // What does NOT get retried:
 //   - HTTP 4xx client errors (except 429)
 //   - HTTP 2xx/3xx successful responses
-//   - Requests without GetBody (non-replayable)
+//   - Requests without GetBody (non-replayable), unless buffered with WithMaxRetryBodyBytes;
+//     the first response or error is returned instead of re-sending an empty body
+//   - Status codes set with WithNonRetryableStatusCodes, e.g. 501 Not Implemented
+//   - Permanent transport errors, as classified by DefaultRetryableError or the
+//     function set with WithRetryableErrorFunc (TLS certificate errors, context
+//     cancellation and deadlines)
+//
+// Connection resets (see IsConnectionReset) and EOF on a reused connection, common when a
+// pooled keep-alive connection is closed by the server as it is reused, are retried even
+// when the function set with WithRetryableErrorFunc rejects them, unless disabled with
+// WithRetryOnConnectionReset.
 //
 // Available retry strategies:
 //
@@ -205,6 +326,9 @@
 //     strategy := httpx.ExponentialBackoff(500*time.Millisecond, 10*time.Second)
 //     // Wait times: 500ms → 1s → 2s → 4s → 8s (capped at maxDelay)
 //
+//     strategy := httpx.ExponentialBackoffWithMultiplier(time.Second, 10*time.Second, 1.5)
+//     // Wait times: 1s → 1.5s → 2.25s → 3.375s (custom growth factor)
+//
 //  2. Fixed Delay (useful for predictable retry timing):
 //
 //     strategy := httpx.FixedDelay(1*time.Second)
@@ -221,6 +345,7 @@
 //	    httpx.WithMaxRetriesRetry(3),
 //	    httpx.WithRetryStrategyRetry(httpx.ExponentialBackoff(500*time.Millisecond, 10*time.Second)),
 //	    httpx.WithBaseTransport(http.DefaultTransport),
+//	    httpx.WithDefaultHeadersRetry(map[string]string{"User-Agent": "my-service/1.0"}),
 //	)
 //
 // # Client Builder
@@ -262,6 +387,16 @@
 //
 //	    Build()
 //
+// BuildE is the strict variant of Build: it returns an error instead of substituting
+// defaults for out-of-range settings or ignoring an invalid proxy URL, which would
+// otherwise send requests directly.
+//
+// Share a connection pool between clients with WithBaseTransport, which uses an existing
+// transport under the retry layer instead of building a new http.Transport:
+//
+//	shared := &http.Transport{MaxIdleConnsPerHost: 50}
+//	client := httpx.NewClientBuilder().WithBaseTransport(shared).Build()
+//
 // Combine with GenericClient:
 //
 //	retryClient := httpx.NewClientBuilder().
@@ -402,6 +537,13 @@
 //	    httpx.WithMaxRetriesRetry(5),
 //	)
 //
+// Proxy with custom CONNECT headers:
+//
+//	client := httpx.NewClientBuilder().
+//	    WithProxy("http://proxy.example.com:8080").
+//	    WithProxyConnectHeader(http.Header{"X-Proxy-Route": {"eu-west"}}).
+//	    Build()
+//
 // Disable proxy (override environment variables):
 //
 //	client := httpx.NewClientBuilder().
@@ -450,7 +592,7 @@
 // The package uses slog for debug logging. Enable debug logging to see:
 //   - Request details (method, URL, headers, body)
 //   - Response details (status, headers, body)
-//   - Retry attempts and delays
+//   - Retry attempts and delays (with delay_source and computed_delay)
 //
 // Enable debug logging:
 //
@@ -1,9 +1,18 @@
 package httpx
 
 import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
+	"math"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -36,6 +45,9 @@ const (
 	// DefaultMaxDelay is the default maximum delay for backoff strategies
 	DefaultMaxDelay = 10 * time.Second
 
+	// DefaultRetryMultiplier is the default growth factor of exponential backoff delays
+	DefaultRetryMultiplier = 2.0
+
 	// DefaultMaxIdleConns is the default maximum number of idle connections
 	DefaultMaxIdleConns = 100
 
@@ -102,11 +114,43 @@ func (s Strategy) IsValid() bool {
 	}
 }
 
+// PhaseTimeouts bundles the timeouts of the phases of a request, set together with
+// WithPhaseTimeouts. Zero fields leave the corresponding setting unchanged.
+type PhaseTimeouts struct {
+	// Connect bounds establishing a TCP connection, including DNS resolution (default 30s)
+	Connect time.Duration
+
+	// TLSHandshake bounds the TLS handshake, as set with WithTLSHandshakeTimeout
+	TLSHandshake time.Duration
+
+	// Write bounds each write of the request to the connection, restarting on every write,
+	// so that a peer that stops reading the request body is caught (default no limit)
+	Write time.Duration
+
+	// ResponseHeader bounds waiting for the response headers once the request, including
+	// its body, is written, as set with WithResponseHeaderTimeout (default no limit)
+	ResponseHeader time.Duration
+
+	// BodyRead bounds each read of the response body, as set with WithBodyReadTimeout
+	// (default no limit)
+	BodyRead time.Duration
+
+	// Attempt bounds each attempt, from connecting to reading the response body, including
+	// writing the request, as set with WithTimeout. A timeout set with WithPerAttemptTimeout
+	// takes precedence over it
+	Attempt time.Duration
+
+	// Overall bounds the whole request, including all retries and retry delays, as set with
+	// WithMaxResponseTime (default no limit)
+	Overall time.Duration
+}
+
 // Client is a custom HTTP client with configurable settings
 // and retry strategies. Works transparently with existing request headers.
 // It preserves all headers without requiring explicit configuration.
 type Client struct {
-	retryStrategyType     Strategy // Store the type, not the function
+	retryStrategyType     Strategy      // Store the type, not the function
+	retryStrategyFunc     RetryStrategy // Custom strategy function, takes precedence over retryStrategyType
 	maxIdleConns          int
 	idleConnTimeout       time.Duration
 	tlsHandshakeTimeout   time.Duration
@@ -116,9 +160,55 @@ type Client struct {
 	maxRetries            int
 	retryBaseDelay        time.Duration
 	retryMaxDelay         time.Duration
+	retryMultiplier       float64 // Growth factor of exponential delays (0 = DefaultRetryMultiplier)
 	disableKeepAlive      bool
-	proxyURL              string       // Proxy URL (e.g., "http://proxy.example.com:8080")
-	logger                *slog.Logger // Optional logger (nil = no logging)
+	disableCompression    bool          // Do not request gzip responses transparently
+	proxyURL              string        // Proxy URL (e.g., "http://proxy.example.com:8080")
+	logger                *slog.Logger  // Optional logger (nil = no logging)
+	maxRetriesForStatus   map[int]int   // Per-status-code overrides of maxRetries
+	nonRetryableStatus    map[int]bool  // 5xx and 429 status codes that are never retried
+	deadlineHeader        string        // Header carrying the remaining request deadline
+	attemptsHeader        string        // Response header carrying the number of attempts
+	tlsServerName         string        // TLS ServerName (SNI) override
+	proxyConnectHeader    http.Header   // Headers sent on proxy CONNECT requests
+	minTLSVersion         uint16        // Minimum TLS version (0 = Go default)
+	dnsCacheTTL           time.Duration // How long resolved host addresses are cached (0 = no cache)
+	maxHeaderBytes        int64         // Limit on response header bytes (0 = Go default)
+	localAddr             net.Addr      // Local address outgoing connections are bound to (nil = chosen by the OS)
+	dialTimeout           time.Duration // Timeout of establishing connections (0 = dialer default)
+	writeTimeout          time.Duration // Timeout of each write to a connection (0 = no limit)
+	maxConnLifetime       time.Duration // Age after which pooled connections are not reused (0 = no limit)
+	responseHeaderTimeout time.Duration // Timeout of waiting for response headers (0 = no limit)
+	bodyReadTimeout       time.Duration // Timeout of each read of the response body (0 = no limit)
+	unixSocket            string        // Path of a unix domain socket all connections are dialed to
+
+	retryBudgetRatio        float64 // Retries allowed per request within the retry budget window
+	retryBudgetMinPerSecond int     // Retries per second always allowed by the retry budget
+
+	retryableError  func(error) bool // Decides whether transport errors are retried (nil = DefaultRetryableError)
+	maxResponseTime time.Duration    // Bound on a request including all retries (0 = no limit)
+
+	perAttemptTimeout time.Duration // Bound on each attempt of a request (0 = no limit)
+
+	noConnectionResetRetry bool // Leave connection resets to retryableError instead of always retrying them
+
+	maxRetryBodyBytes int // Largest body without GetBody buffered for retries (0 = no buffering)
+
+	absoluteMaxDelay  time.Duration // Hard ceiling on every retry delay (0 = no ceiling)
+	retryAfterMaxWait time.Duration // Longest Retry-After delay honored before giving up (0 = no limit)
+	respectRetryAfter bool          // Lengthen retry delays to the Retry-After header of 429 and 503 responses
+
+	baseTransport http.RoundTripper // Transport under the retry layer (nil = build a standard transport)
+}
+
+// clone returns a copy of the client configuration that shares no mutable state with c.
+func (c *Client) clone() *Client {
+	clone := *c
+	clone.maxRetriesForStatus = copyMaxRetriesForStatus(c.maxRetriesForStatus)
+	clone.nonRetryableStatus = maps.Clone(c.nonRetryableStatus)
+	clone.proxyConnectHeader = c.proxyConnectHeader.Clone()
+
+	return &clone
 }
 
 // ClientBuilder is a builder for creating a custom HTTP client
@@ -171,6 +261,72 @@ func (b *ClientBuilder) WithTLSHandshakeTimeout(tlsHandshakeTimeout time.Duratio
 	return b
 }
 
+// WithResponseHeaderTimeout bounds how long the client waits for the response headers once
+// the request, including its body, is written, through http.Transport.ResponseHeaderTimeout.
+// Exceeding it fails the attempt, which is retried like other transient errors. It does not
+// bound reading the body; see WithBodyReadTimeout for that. Pass 0 to wait without a limit
+// other than the timeout of the attempt (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithResponseHeaderTimeout(timeout time.Duration) *ClientBuilder {
+	b.client.responseHeaderTimeout = timeout
+
+	return b
+}
+
+// WithBodyReadTimeout bounds how long each read of a response body waits for data, to
+// protect against servers that send the headers quickly and then stall the body, e.g.
+// slowloris-style. The deadline restarts with every read, so large bodies that keep
+// streaming are not cut off, and it only runs while a read is in progress, so callers that
+// consume the body slowly are not affected. A read that receives no data in time aborts
+// the request and fails with an error wrapping ErrBodyReadTimeout. Pass 0 to read without
+// a limit other than the timeout of the attempt (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithBodyReadTimeout(timeout time.Duration) *ClientBuilder {
+	b.client.bodyReadTimeout = timeout
+
+	return b
+}
+
+// WithPhaseTimeouts sets the timeouts of the phases of a request in one place: connecting,
+// the TLS handshake, writing the request, waiting for response headers, reading the response
+// body, each attempt and the whole request.
+// Zero fields leave the corresponding setting unchanged, so it can be combined with the
+// individual setters; the Attempt and TLSHandshake values are validated like those of
+// WithTimeout and WithTLSHandshakeTimeout. Negative values are ignored. A timeout set with
+// WithPerAttemptTimeout takes precedence over the Attempt value.
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithPhaseTimeouts(timeouts PhaseTimeouts) *ClientBuilder {
+	if timeouts.Connect > 0 {
+		b.client.dialTimeout = timeouts.Connect
+	}
+
+	if timeouts.TLSHandshake > 0 {
+		b.client.tlsHandshakeTimeout = timeouts.TLSHandshake
+	}
+
+	if timeouts.Write > 0 {
+		b.client.writeTimeout = timeouts.Write
+	}
+
+	if timeouts.ResponseHeader > 0 {
+		b.client.responseHeaderTimeout = timeouts.ResponseHeader
+	}
+
+	if timeouts.BodyRead > 0 {
+		b.client.bodyReadTimeout = timeouts.BodyRead
+	}
+
+	if timeouts.Attempt > 0 {
+		b.client.timeout = timeouts.Attempt
+	}
+
+	if timeouts.Overall > 0 {
+		b.client.maxResponseTime = timeouts.Overall
+	}
+
+	return b
+}
+
 // WithExpectContinueTimeout sets the expect continue timeout
 // and returns the ClientBuilder for method chaining
 func (b *ClientBuilder) WithExpectContinueTimeout(expectContinueTimeout time.Duration) *ClientBuilder {
@@ -187,6 +343,17 @@ func (b *ClientBuilder) WithDisableKeepAlive(disableKeepAlive bool) *ClientBuild
 	return b
 }
 
+// WithDisableCompression sets whether to disable the transparent compression of the
+// transport, which requests gzip responses with an Accept-Encoding header and decompresses
+// them. Disabling it helps when debugging or talking to servers that mishandle
+// Accept-Encoding; an Accept-Encoding header set on the request is always sent as is
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithDisableCompression(disableCompression bool) *ClientBuilder {
+	b.client.disableCompression = disableCompression
+
+	return b
+}
+
 // WithMaxIdleConnsPerHost sets the maximum number of idle connections per host
 // and returns the ClientBuilder for method chaining
 func (b *ClientBuilder) WithMaxIdleConnsPerHost(maxIdleConnsPerHost int) *ClientBuilder {
@@ -195,7 +362,10 @@ func (b *ClientBuilder) WithMaxIdleConnsPerHost(maxIdleConnsPerHost int) *Client
 	return b
 }
 
-// WithTimeout sets the timeout for HTTP requests
+// WithTimeout sets the timeout for HTTP requests. It is enforced per attempt by the retry
+// transport, including reading the response body, so every retry gets the full timeout
+// instead of sharing a single http.Client.Timeout across all attempts and retry delays.
+// Use WithPerAttemptTimeout to override it, and WithMaxResponseTime to bound the whole request
 // and returns the ClientBuilder for method chaining
 func (b *ClientBuilder) WithTimeout(timeout time.Duration) *ClientBuilder {
 	b.client.timeout = timeout
@@ -211,6 +381,28 @@ func (b *ClientBuilder) WithMaxRetries(maxRetries int) *ClientBuilder {
 	return b
 }
 
+// WithMaxRetriesForStatus sets per-status-code retry limits that override
+// the global maximum number of retries for matching responses, e.g.
+// map[int]int{429: 10, 500: 2}. Unlisted status codes use the global maximum.
+// Negative limits are ignored.
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithMaxRetriesForStatus(maxRetriesForStatus map[int]int) *ClientBuilder {
+	b.client.maxRetriesForStatus = copyMaxRetriesForStatus(maxRetriesForStatus)
+
+	return b
+}
+
+// WithNonRetryableStatusCodes sets status codes that are never retried even though they
+// are 5xx or 429, e.g. 501 Not Implemented or other deterministic server errors.
+// Responses with these status codes are returned as is after the first attempt.
+// Call it without arguments to retry all 5xx and 429 responses (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithNonRetryableStatusCodes(codes ...int) *ClientBuilder {
+	b.client.nonRetryableStatus = newStatusCodeSet(codes)
+
+	return b
+}
+
 // WithRetryBaseDelay sets the base delay for retry strategies
 // and returns the ClientBuilder for method chaining
 func (b *ClientBuilder) WithRetryBaseDelay(baseDelay time.Duration) *ClientBuilder {
@@ -227,6 +419,17 @@ func (b *ClientBuilder) WithRetryMaxDelay(maxDelay time.Duration) *ClientBuilder
 	return b
 }
 
+// WithRetryMultiplier sets the factor by which the delay grows on each attempt of the
+// exponential and jitter backoff strategies, e.g. 1.5 for gentler or 3 for more aggressive
+// growth than the default doubling. Delays are still capped at the maximum retry delay.
+// Values below 1 fall back to DefaultRetryMultiplier with a warning.
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithRetryMultiplier(multiplier float64) *ClientBuilder {
+	b.client.retryMultiplier = multiplier
+
+	return b
+}
+
 // WithRetryStrategy sets the retry strategy type
 // and returns the ClientBuilder for method chaining
 func (b *ClientBuilder) WithRetryStrategy(strategy Strategy) *ClientBuilder {
@@ -235,6 +438,132 @@ func (b *ClientBuilder) WithRetryStrategy(strategy Strategy) *ClientBuilder {
 	return b
 }
 
+// WithRetryStrategyFunc sets a custom retry strategy function, e.g. a Fibonacci backoff,
+// that computes the delay before each retry. It takes precedence over the strategy type
+// set with WithRetryStrategy, and the base and maximum retry delays are not applied to it.
+// Retry-After headers honored with WithRespectRetryAfter are still capped at the maximum
+// retry delay. Pass nil to use the strategy type (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithRetryStrategyFunc(strategy RetryStrategy) *ClientBuilder {
+	b.client.retryStrategyFunc = strategy
+
+	return b
+}
+
+// WithRetryableErrorFunc sets the function deciding whether a transport error (a request that
+// failed without a response) is retried. It is not consulted for retryable status codes.
+// Pass nil to use DefaultRetryableError, which does not retry context cancellation, deadlines
+// and TLS certificate errors (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithRetryableErrorFunc(fn func(error) bool) *ClientBuilder {
+	b.client.retryableError = fn
+
+	return b
+}
+
+// WithRetryOnConnectionReset sets whether connection resets, as reported by IsConnectionReset,
+// and EOF on a reused pooled connection are always retried. They are common when a pooled
+// keep-alive connection is closed by the server just as it is reused, so they are retried by
+// default even when WithRetryableErrorFunc sets a stricter policy. Pass false to leave them to
+// the retryable error function.
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithRetryOnConnectionReset(enabled bool) *ClientBuilder {
+	b.client.noConnectionResetRetry = !enabled
+
+	return b
+}
+
+// WithRespectRetryAfter sets whether the client waits as long as the Retry-After header
+// (seconds or HTTP-date) of 429 and 503 responses asks, when that is longer than the delay
+// of the retry strategy. The wait is capped at the maximum retry delay set with
+// WithRetryMaxDelay, or at the limit set with WithRetryAfterMaxWait, which enables it too.
+// Pass false to always use the strategy delay (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithRespectRetryAfter(enabled bool) *ClientBuilder {
+	b.client.respectRetryAfter = enabled
+
+	return b
+}
+
+// WithAbsoluteMaxDelay sets an absolute ceiling on every single retry delay, enforced as the
+// final clamp after the delay of the retry strategy, its jitter and any Retry-After header
+// are computed. Unlike WithRetryMaxDelay, which configures the strategy, it applies to every
+// strategy, including custom ones, and guarantees that no retry sleeps longer than d, e.g.
+// when a misbehaving or malicious server sends "Retry-After: 3600".
+// Pass 0 to disable the ceiling (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithAbsoluteMaxDelay(d time.Duration) *ClientBuilder {
+	b.client.absoluteMaxDelay = d
+
+	return b
+}
+
+// WithRetryAfterMaxWait sets the longest Retry-After delay the client honors. A 429 or 503
+// response asking for a longer wait is not retried: the request fails at once with an error
+// wrapping ErrRetryAfterTooLong and the status code, instead of sleeping for, say, an hour.
+// Unlike WithAbsoluteMaxDelay, which shortens such a wait and retries anyway, it aborts the
+// retries. Setting it also enables WithRespectRetryAfter: shorter Retry-After delays are
+// honored in full, even beyond the maximum retry delay set with WithRetryMaxDelay.
+// Pass 0 to disable the limit (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithRetryAfterMaxWait(d time.Duration) *ClientBuilder {
+	b.client.retryAfterMaxWait = d
+
+	return b
+}
+
+// WithMaxResponseTime bounds the total time of a request, including all retry attempts and
+// the delays between them. Unlike the client timeout, exceeding it returns an error wrapping
+// ErrResponseTooSlow, so slow responses can be told apart from other failures. The limit
+// also covers reading the response body. It is tracked by the retry transport.
+// Pass 0 to disable the limit (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithMaxResponseTime(maxResponseTime time.Duration) *ClientBuilder {
+	b.client.maxResponseTime = maxResponseTime
+
+	return b
+}
+
+// WithPerAttemptTimeout bounds each attempt of a request, including reading its response
+// body, with a fresh timeout, overriding the timeout set with WithTimeout. Unlike a timeout
+// spanning all attempts and retry delays, which leaves later retries little or no time,
+// every retry gets the full timeout. Attempts that time out are retried; when the last one
+// does, the error wraps ErrAttemptTimeout. Combine it with WithMaxResponseTime to also bound
+// the whole request. Pass 0 to use the timeout set with WithTimeout (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithPerAttemptTimeout(timeout time.Duration) *ClientBuilder {
+	b.client.perAttemptTimeout = timeout
+
+	return b
+}
+
+// WithMaxRetryBodyBytes makes request bodies that cannot be replayed, i.e. that have no
+// GetBody such as streaming readers, retryable by buffering them in memory when they are
+// at most n bytes. Larger bodies are streamed without buffering and are not retried, so
+// memory use stays predictable for mixed small and large uploads. Bodies with a GetBody,
+// e.g. those built by RequestBuilder or from a bytes.Reader, are always replayable.
+// Pass 0 to disable buffering (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithMaxRetryBodyBytes(n int) *ClientBuilder {
+	b.client.maxRetryBodyBytes = n
+
+	return b
+}
+
+// WithBaseTransport sets the transport used under the retry layer instead of a new
+// http.Transport, e.g. a pre-tuned transport or one shared across clients to share
+// their connection pool. Retries, timeouts and logging still apply. Options that
+// configure the standard transport (proxy, proxy CONNECT headers, TLS server name,
+// minimum TLS version and DNS cache) are ignored with a warning, and connection pool
+// settings have no effect; configure them on the transport directly.
+// Pass nil to build a standard transport (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithBaseTransport(transport http.RoundTripper) *ClientBuilder {
+	b.client.baseTransport = transport
+
+	return b
+}
+
 // WithRetryStrategyAsString sets the retry strategy type from a string
 // and returns the ClientBuilder for method chaining
 func (b *ClientBuilder) WithRetryStrategyAsString(strategy string) *ClientBuilder {
@@ -271,6 +600,250 @@ func (b *ClientBuilder) WithProxy(proxyURL string) *ClientBuilder {
 	return b
 }
 
+// WithProxyConnectHeader sets headers sent on the CONNECT request when tunneling HTTPS
+// through an HTTP proxy, e.g. proxy authentication or routing hints required by some
+// corporate proxies. The header is copied. Headers with CR or LF characters in a key or
+// value are rejected by Build, which logs a warning and proceeds without them.
+// Pass nil to send no extra CONNECT headers (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithProxyConnectHeader(header http.Header) *ClientBuilder {
+	b.client.proxyConnectHeader = header.Clone()
+
+	return b
+}
+
+// WithTLSServerName sets the server name sent via SNI and used to verify the server
+// certificate, overriding the host of the request URL. This is useful when connecting
+// to a host by IP address (e.g. behind a load balancer) that serves a certificate for
+// a specific name. It is set on the ServerName field of the transport's TLS client
+// configuration, which is created if needed.
+// Pass an empty string to use the request host (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithTLSServerName(serverName string) *ClientBuilder {
+	b.client.tlsServerName = serverName
+
+	return b
+}
+
+// WithMinTLSVersion sets the minimum TLS version accepted by the client, e.g.
+// tls.VersionTLS12 or tls.VersionTLS13. It is set on the MinVersion field of the
+// transport's TLS client configuration, which is created if needed. Unknown versions
+// are ignored by Build with a warning.
+// Pass 0 to use Go's default minimum version (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithMinTLSVersion(version uint16) *ClientBuilder {
+	b.client.minTLSVersion = version
+
+	return b
+}
+
+// WithMaxHeaderBytes limits the size of the response headers the client accepts, protecting
+// it against servers returning pathologically large headers. It sets MaxResponseHeaderBytes
+// on the transport: responses whose headers exceed the limit fail with a transport error
+// ("server response headers exceeded N bytes") and are not read further. Negative values
+// are ignored by Build with a warning.
+// Pass 0 to use Go's default limit of 1 MB (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithMaxHeaderBytes(maxHeaderBytes int64) *ClientBuilder {
+	b.client.maxHeaderBytes = maxHeaderBytes
+
+	return b
+}
+
+// WithLocalAddr binds outgoing connections to the given local address, e.g. to send
+// requests from a specific source IP on multi-homed hosts for egress IP allowlisting.
+// The address must match the network dialed, typically a *net.TCPAddr; leave its port
+// at 0 so that each connection gets an ephemeral port. The address is set on the
+// net.Dialer that Build creates for the transport.
+// Pass nil to let the operating system choose the address (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithLocalAddr(addr net.Addr) *ClientBuilder {
+	b.client.localAddr = addr
+
+	return b
+}
+
+// WithUnixSocket sends all requests over the unix domain socket at the given path,
+// e.g. "/var/run/docker.sock", to talk to local daemons exposing an HTTP API.
+// Every connection dials the socket regardless of the request URL, so the URL scheme
+// and host become placeholders ("http://unix/v1.45/containers/json"); the path, query
+// and Host header are still sent as usual. The local address and DNS cache options
+// do not apply to unix socket connections and are ignored.
+// Pass an empty string to dial the URL host over TCP (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithUnixSocket(path string) *ClientBuilder {
+	b.client.unixSocket = path
+
+	return b
+}
+
+// WithMaxConnLifetime caps the age of pooled connections, independently of the idle
+// connection timeout, so that long-running clients pick up backends that rotate their
+// certificates or addresses, e.g. behind a cloud load balancer. http.Transport has no such
+// limit, so connections are dialed through a custom DialContext that records their creation
+// time: a connection older than the lifetime is closed once it is idle in the pool, and later
+// requests are sent on a new connection. Requests in flight are never interrupted; a request
+// that outlives the lifetime keeps its connection until its response is read. HTTP/2
+// connections, which are shared by concurrent requests, are not retired.
+// Pass 0 to reuse connections regardless of their age (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithMaxConnLifetime(lifetime time.Duration) *ClientBuilder {
+	b.client.maxConnLifetime = lifetime
+
+	return b
+}
+
+// WithDNSCache caches the addresses resolved for each host for the given TTL, so that
+// new connections to the same host skip the DNS lookup. This reduces latency for
+// high-throughput clients talking to a few hosts. Resolution uses the standard library
+// resolver from a custom DialContext; resolved addresses are tried in order and the
+// dial respects context cancellation. The cache is created by Build and shared by all
+// requests of the built client.
+// Pass 0 to resolve on every new connection (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithDNSCache(ttl time.Duration) *ClientBuilder {
+	b.client.dnsCacheTTL = ttl
+
+	return b
+}
+
+// WithDeadlinePropagationHeader sets the name of a header used to propagate the request
+// context deadline across service boundaries, e.g. "X-Request-Timeout" or "grpc-timeout".
+// When the request context has a deadline, the remaining time is computed before each
+// attempt and sent in whole milliseconds ("1500"), or in the gRPC timeout format ("1500m")
+// for "grpc-timeout". Requests without a deadline are sent unchanged.
+// Pass an empty string to disable deadline propagation (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithDeadlinePropagationHeader(name string) *ClientBuilder {
+	b.client.deadlineHeader = name
+
+	return b
+}
+
+// WithAttemptsHeader sets the name of a response header, e.g. "X-Httpx-Attempts", that is
+// set to the number of times the underlying transport was invoked for the request: "1" for
+// a request that succeeded without retries, "3" for one that succeeded on the second retry.
+// This makes retry behavior observable to callers and integration tests without mocks.
+// The header is set on every response returned, not on errors after all retries failed.
+// Pass an empty string to disable it (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithAttemptsHeader(name string) *ClientBuilder {
+	b.client.attemptsHeader = name
+
+	return b
+}
+
+// WithRetryBudget limits the retries of the client across all of its requests, so that a
+// failing backend does not receive a storm of retries on top of the regular traffic.
+// Within a sliding window of 10 seconds, retries are allowed while their number stays
+// below minPerSecond*10 plus ratio times the number of requests sent. For example, a
+// ratio of 0.2 and a minPerSecond of 10 allow retries for at most 20% of the requests,
+// and always at least 10 retries per second. When the budget is exhausted, a failed
+// request is not retried and returns an error wrapping ErrRetryBudgetExhausted.
+// The budget is created by Build and shared by all requests of the built client.
+// Pass a negative ratio, or zero for both values, to disable the budget (default behavior).
+// and returns the ClientBuilder for method chaining
+func (b *ClientBuilder) WithRetryBudget(ratio float64, minPerSecond int) *ClientBuilder {
+	b.client.retryBudgetRatio = ratio
+	b.client.retryBudgetMinPerSecond = minPerSecond
+
+	return b
+}
+
+// BuildE is like Build, but fails with an error instead of silently substituting defaults
+// for settings that are out of their valid range or proceeding without settings that cannot
+// be applied, e.g. an invalid proxy URL, which Build ignores and which would send requests
+// directly instead of through the proxy. All invalid settings are reported together, which
+// lets tests and CI catch misconfiguration. Use Build for the lenient behavior.
+func (b *ClientBuilder) BuildE() (*http.Client, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+
+	return b.Build(), nil
+}
+
+// validate returns the errors of the settings that Build would replace with defaults or
+// ignore, joined together.
+func (b *ClientBuilder) validate() error {
+	c := b.client
+	errs := []error{
+		checkRange("max idle connections", c.maxIdleConns, ValidMinIdleConns, ValidMaxIdleConns),
+		checkRange("idle connection timeout", c.idleConnTimeout, ValidMinIdleConnTimeout, ValidMaxIdleConnTimeout),
+		checkRange("TLS handshake timeout", c.tlsHandshakeTimeout, ValidMinTLSHandshakeTimeout, ValidMaxTLSHandshakeTimeout),
+		checkRange("expect continue timeout", c.expectContinueTimeout, ValidMinExpectContinueTimeout, ValidMaxExpectContinueTimeout),
+		checkRange("max idle connections per host", c.maxIdleConnsPerHost, ValidMinIdleConnsPerHost, ValidMaxIdleConnsPerHost),
+		checkRange("timeout", c.timeout, ValidMinTimeout, ValidMaxTimeout),
+		checkRange("max retries", c.maxRetries, ValidMinRetries, ValidMaxRetries),
+		checkRange("retry base delay", c.retryBaseDelay, ValidMinBaseDelay, ValidMaxBaseDelay),
+		checkRange("retry max delay", c.retryMaxDelay, ValidMinMaxDelay, ValidMaxMaxDelay),
+	}
+
+	if c.retryMultiplier != 0 && !isValidRetryMultiplier(c.retryMultiplier) {
+		errs = append(errs, fmt.Errorf("retry multiplier %v must be at least 1 and finite", c.retryMultiplier))
+	}
+
+	if !c.retryStrategyType.IsValid() {
+		errs = append(errs, fmt.Errorf("invalid retry strategy %q", c.retryStrategyType))
+	}
+
+	if c.maxRetryBodyBytes < 0 {
+		errs = append(errs, fmt.Errorf("max retry body bytes %d cannot be negative", c.maxRetryBodyBytes))
+	}
+
+	if c.maxHeaderBytes < 0 {
+		errs = append(errs, fmt.Errorf("max header bytes %d cannot be negative", c.maxHeaderBytes))
+	}
+
+	if c.minTLSVersion != 0 && !isValidTLSVersion(c.minTLSVersion) {
+		errs = append(errs, fmt.Errorf("invalid minimum TLS version %#x", c.minTLSVersion))
+	}
+
+	if c.proxyURL != "" {
+		if err := validateProxyURL(c.proxyURL); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(c.proxyConnectHeader) > 0 {
+		if err := validateHeaderFields(c.proxyConnectHeader); err != nil {
+			errs = append(errs, fmt.Errorf("invalid proxy CONNECT header: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkRange returns an error naming the setting if value is outside [lower, upper].
+func checkRange[V cmp.Ordered](name string, value, lower, upper V) error {
+	if value < lower || value > upper {
+		return fmt.Errorf("%s %v out of range [%v, %v]", name, value, lower, upper)
+	}
+
+	return nil
+}
+
+// validateProxyURL reports whether proxyURL is an absolute URL with a proxy scheme
+// supported by http.Transport and a host.
+func validateProxyURL(proxyURL string) error {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("invalid proxy URL %q: scheme must be http, https, socks5 or socks5h", proxyURL)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("invalid proxy URL %q: missing host", proxyURL)
+	}
+
+	return nil
+}
+
 // Build creates and returns a new HTTP client with the specified settings
 // and retry strategy. The client works transparently, preserving any existing
 // headers in requests without requiring explicit configuration.
@@ -347,6 +920,14 @@ func (b *ClientBuilder) Build() *http.Client {
 		b.client.retryMaxDelay = DefaultMaxDelay
 	}
 
+	if b.client.retryMultiplier != 0 && !isValidRetryMultiplier(b.client.retryMultiplier) {
+		if b.client.logger != nil {
+			b.client.logger.Warn("Invalid retry multiplier, using default value", "invalidValue", b.client.retryMultiplier, "defaultValue", DefaultRetryMultiplier)
+		}
+
+		b.client.retryMultiplier = DefaultRetryMultiplier
+	}
+
 	// Determine the final strategy type, defaulting if necessary
 	finalStrategyType := b.client.retryStrategyType
 	switch finalStrategyType {
@@ -365,13 +946,119 @@ func (b *ClientBuilder) Build() *http.Client {
 	case FixedDelayStrategy:
 		finalRetryStrategy = FixedDelay(b.client.retryBaseDelay)
 	case JitterBackoffStrategy:
-		finalRetryStrategy = JitterBackoff(b.client.retryBaseDelay, b.client.retryMaxDelay)
+		finalRetryStrategy = withJitter(b.exponentialBackoff())
 	case ExponentialBackoffStrategy:
-		finalRetryStrategy = ExponentialBackoff(b.client.retryBaseDelay, b.client.retryMaxDelay)
+		finalRetryStrategy = b.exponentialBackoff()
 	default:
-		finalRetryStrategy = ExponentialBackoff(b.client.retryBaseDelay, b.client.retryMaxDelay)
+		finalRetryStrategy = b.exponentialBackoff()
+	}
+
+	// A custom strategy function takes precedence over the strategy type
+	if b.client.retryStrategyFunc != nil {
+		finalRetryStrategy = b.client.retryStrategyFunc
+	}
+
+	// Use the custom base transport if set, otherwise build a standard transport
+	var baseTransport http.RoundTripper
+	if b.client.baseTransport != nil {
+		baseTransport = b.client.baseTransport
+
+		if b.client.logger != nil && b.client.hasTransportOptions() {
+			b.client.logger.Warn("Custom base transport provided; proxy, TLS, dialer, DNS cache, header size, response header timeout and compression options ignored. Configure them on your custom transport directly.")
+		}
+	} else {
+		baseTransport = b.newTransport()
+	}
+
+	if b.client.maxRetryBodyBytes < 0 && b.client.logger != nil {
+		b.client.logger.Warn("Invalid max retry body bytes, buffering disabled", "invalidValue", b.client.maxRetryBodyBytes)
+	}
+
+	// Create retry transport - this is the only layer needed for transparent operation
+	// It automatically preserves all existing headers without any explicit auth configuration
+	finalTransport := &retryTransport{
+		Transport:     baseTransport,
+		MaxRetries:    b.client.maxRetries,
+		RetryStrategy: finalRetryStrategy,
+		logger:        b.client.logger,
+
+		maxRetriesForStatus: b.client.maxRetriesForStatus,
+		nonRetryableStatus:  b.client.nonRetryableStatus,
+		maxDelay:            b.client.retryMaxDelay,
+		respectRetryAfter:   b.client.respectRetryAfter,
+		deadlineHeader:      b.client.deadlineHeader,
+		attemptsHeader:      b.client.attemptsHeader,
+		budget:              newRetryBudget(b.client.retryBudgetRatio, b.client.retryBudgetMinPerSecond),
+		retryableError:      b.client.retryableError,
+		maxResponseTime:     b.client.maxResponseTime,
+		absoluteMaxDelay:    b.client.absoluteMaxDelay,
+		retryAfterMaxWait:   b.client.retryAfterMaxWait,
+		maxRetryBodyBytes:   b.client.maxRetryBodyBytes,
+		perAttemptTimeout:   b.client.timeout,
+		bodyReadTimeout:     b.client.bodyReadTimeout,
+
+		noConnectionResetRetry: b.client.noConnectionResetRetry,
+		connLifetime:           b.client.maxConnLifetime > 0 && b.client.baseTransport == nil,
+	}
+
+	if b.client.perAttemptTimeout > 0 {
+		finalTransport.perAttemptTimeout = b.client.perAttemptTimeout
+	}
+
+	// Capping can only be reported for the exponential strategies built here
+	if b.client.retryStrategyFunc == nil && finalStrategyType != FixedDelayStrategy {
+		finalTransport.strategyCapping = b.strategyCapping()
+	}
+
+	// Create the HTTP client with the specified settings. The timeout is enforced per
+	// attempt by the retry transport; an http.Client.Timeout would span all attempts and
+	// retry delays and leave little or no time for later retries
+	return &http.Client{
+		Transport: finalTransport,
+	}
+}
+
+// exponentialBackoff returns the exponential backoff strategy for the configured
+// delays, growing by the configured multiplier if one is set.
+func (b *ClientBuilder) exponentialBackoff() RetryStrategy {
+	if b.client.retryMultiplier != 0 && b.client.retryMultiplier != DefaultRetryMultiplier {
+		return ExponentialBackoffWithMultiplier(b.client.retryBaseDelay, b.client.retryMaxDelay, b.client.retryMultiplier)
+	}
+
+	return ExponentialBackoff(b.client.retryBaseDelay, b.client.retryMaxDelay)
+}
+
+// strategyCapping returns a function reporting the delay of the exponential backoff before
+// the maximum retry delay caps it, and whether it was capped. It mirrors
+// ExponentialBackoffWithMultiplier; any jitter is added after capping and is not included.
+func (b *ClientBuilder) strategyCapping() func(attempt int) (time.Duration, bool) {
+	base, maxDelay := b.client.retryBaseDelay, b.client.retryMaxDelay
+
+	multiplier := b.client.retryMultiplier
+	if multiplier == 0 {
+		multiplier = DefaultRetryMultiplier
+	}
+
+	return func(attempt int) (time.Duration, bool) {
+		if attempt == 0 && base > maxDelay {
+			return base, false
+		}
+
+		uncapped := float64(base) * math.Pow(multiplier, float64(attempt))
+		switch {
+		case uncapped <= float64(maxDelay):
+			return time.Duration(uncapped), false
+		case uncapped >= math.MaxInt64:
+			return math.MaxInt64, true
+		default:
+			return time.Duration(uncapped), true
+		}
 	}
+}
 
+// newTransport creates the standard transport configured with the connection pool,
+// DNS cache, TLS and proxy settings of the builder.
+func (b *ClientBuilder) newTransport() *http.Transport {
 	// Create the underlying standard transport
 	transport := &http.Transport{
 		MaxIdleConns:          b.client.maxIdleConns,
@@ -379,7 +1066,36 @@ func (b *ClientBuilder) Build() *http.Client {
 		TLSHandshakeTimeout:   b.client.tlsHandshakeTimeout,
 		ExpectContinueTimeout: b.client.expectContinueTimeout,
 		DisableKeepAlives:     b.client.disableKeepAlive,
+		DisableCompression:    b.client.disableCompression,
 		MaxIdleConnsPerHost:   b.client.maxIdleConnsPerHost,
+		ResponseHeaderTimeout: b.client.responseHeaderTimeout,
+		// A custom TLSClientConfig or DialContext disables HTTP/2 unless it is forced
+		ForceAttemptHTTP2: true,
+	}
+
+	// Dial through a custom dialer if dialer options are set
+	if dial := b.dialContext(); dial != nil {
+		transport.DialContext = dial
+	}
+
+	// Limit the size of response headers if set
+	if b.client.maxHeaderBytes > 0 {
+		transport.MaxResponseHeaderBytes = b.client.maxHeaderBytes
+	} else if b.client.maxHeaderBytes < 0 && b.client.logger != nil {
+		b.client.logger.Warn("Invalid max header bytes, using Go default", "invalidValue", b.client.maxHeaderBytes)
+	}
+
+	// Configure TLS client settings if set
+	if b.client.tlsServerName != "" {
+		ensureTLSClientConfig(transport).ServerName = b.client.tlsServerName
+	}
+
+	if b.client.minTLSVersion != 0 {
+		if isValidTLSVersion(b.client.minTLSVersion) {
+			ensureTLSClientConfig(transport).MinVersion = b.client.minTLSVersion
+		} else if b.client.logger != nil {
+			b.client.logger.Warn("Invalid minimum TLS version, using Go default", "invalidValue", b.client.minTLSVersion)
+		}
 	}
 
 	// Configure proxy if set
@@ -394,18 +1110,157 @@ func (b *ClientBuilder) Build() *http.Client {
 		}
 	}
 
-	// Create retry transport - this is the only layer needed for transparent operation
-	// It automatically preserves all existing headers without any explicit auth configuration
-	finalTransport := &retryTransport{
-		Transport:     transport,
-		MaxRetries:    b.client.maxRetries,
-		RetryStrategy: finalRetryStrategy,
-		logger:        b.client.logger,
+	// Configure proxy CONNECT headers if set
+	if len(b.client.proxyConnectHeader) > 0 {
+		if err := validateHeaderFields(b.client.proxyConnectHeader); err != nil {
+			if b.client.logger != nil {
+				b.client.logger.Warn("Invalid proxy CONNECT header, proceeding without it", "error", err)
+			}
+		} else {
+			transport.ProxyConnectHeader = b.client.proxyConnectHeader.Clone()
+		}
 	}
 
-	// Create the HTTP client with the specified settings
-	return &http.Client{
-		Timeout:   b.client.timeout,
-		Transport: finalTransport,
+	return transport
+}
+
+// dialContext returns the dial function for the standard transport, wrapping the one of
+// baseDialContext to bound writes and to retire connections older than the maximum lifetime
+// if set. It returns nil to keep the default dialer.
+func (b *ClientBuilder) dialContext() dialContextFunc {
+	dial := b.baseDialContext()
+
+	// Bound each write to the connection if a write timeout is set
+	if b.client.writeTimeout > 0 {
+		if dial == nil {
+			dial = newDefaultDialer().DialContext
+		}
+
+		dial = withWriteTimeout(dial, b.client.writeTimeout)
+	}
+
+	// Retire connections older than the maximum lifetime once they are idle
+	if b.client.maxConnLifetime > 0 {
+		if dial == nil {
+			dial = newDefaultDialer().DialContext
+		}
+
+		dial = withConnLifetime(dial, b.client.maxConnLifetime)
+	}
+
+	return dial
+}
+
+// baseDialContext returns the function dialing new connections: it dials the unix socket
+// if set, or uses a dialer bound to the local address and the DNS cache if they are set.
+// It returns nil to keep the default dialer.
+func (b *ClientBuilder) baseDialContext() dialContextFunc {
+	if b.client.unixSocket != "" {
+		return unixSocketDialContext(b.client.unixSocket)
+	}
+
+	if b.client.localAddr == nil && b.client.dnsCacheTTL <= 0 && b.client.dialTimeout <= 0 {
+		return nil
+	}
+
+	dialer := newDefaultDialer()
+	dialer.LocalAddr = b.client.localAddr
+
+	if b.client.dialTimeout > 0 {
+		dialer.Timeout = b.client.dialTimeout
 	}
+
+	dial := dialContextFunc(dialer.DialContext)
+
+	// Resolve host names through the DNS cache if enabled
+	if b.client.dnsCacheTTL > 0 {
+		dial = newDNSCache(b.client.dnsCacheTTL).dialContext(dial)
+	}
+
+	return dial
+}
+
+// unixSocketDialContext returns a dial function that connects to the unix domain socket
+// at path, ignoring the network and address of the request.
+func unixSocketDialContext(path string) dialContextFunc {
+	dialer := newDefaultDialer()
+
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", path)
+	}
+}
+
+// withWriteTimeout returns a dial function wrapping the connections of dial, so that each
+// write fails if it does not complete within timeout.
+func withWriteTimeout(dial dialContextFunc, timeout time.Duration) dialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		return &writeTimeoutConn{Conn: conn, timeout: timeout}, nil
+	}
+}
+
+// writeTimeoutConn is a connection whose writes fail after a timeout. The deadline is
+// set before every write, so it bounds a stalled write, not the whole request.
+type writeTimeoutConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *writeTimeoutConn) Write(p []byte) (int, error) {
+	if err := c.Conn.SetWriteDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+
+	return c.Conn.Write(p)
+}
+
+// hasTransportOptions reports whether options that configure the standard transport,
+// and therefore do not apply to a custom base transport, are set.
+func (c *Client) hasTransportOptions() bool {
+	return c.proxyURL != "" || len(c.proxyConnectHeader) > 0 || c.tlsServerName != "" ||
+		c.minTLSVersion != 0 || c.dnsCacheTTL > 0 || c.maxHeaderBytes != 0 || c.localAddr != nil || c.unixSocket != "" ||
+		c.dialTimeout > 0 || c.writeTimeout > 0 || c.responseHeaderTimeout > 0 || c.maxConnLifetime > 0 ||
+		c.disableCompression
+}
+
+// ensureTLSClientConfig returns the TLS client configuration of the transport,
+// creating an empty one if none is set.
+func ensureTLSClientConfig(transport *http.Transport) *tls.Config {
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+
+	return transport.TLSClientConfig
+}
+
+// isValidTLSVersion reports whether version is a TLS version known to crypto/tls.
+func isValidTLSVersion(version uint16) bool {
+	switch version {
+	case tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateHeaderFields returns an error if a key or value of the header contains
+// CR or LF characters, which would allow injecting additional header lines.
+func validateHeaderFields(header http.Header) error {
+	for key, values := range header {
+		if strings.ContainsAny(key, "\r\n") {
+			return fmt.Errorf("invalid header key %q: contains CR or LF", key)
+		}
+
+		for _, value := range values {
+			if strings.ContainsAny(value, "\r\n") {
+				return fmt.Errorf("invalid value for header %q: contains CR or LF", key)
+			}
+		}
+	}
+
+	return nil
 }
@@ -1,8 +1,18 @@
 package httpx
 
 import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -97,7 +107,7 @@ func TestClientBuilder_Build_LargeTimeout(t *testing.T) {
 		Build()
 
 	assertNotNil(t, httpClient)
-	assertEqual(t, largeTimeout, httpClient.Timeout)
+	assertEqual(t, largeTimeout, attemptTimeout(t, httpClient))
 }
 
 func TestClientBuilder_Build(t *testing.T) {
@@ -125,7 +135,7 @@ func TestClientBuilder_Build(t *testing.T) {
 	assertNotNil(t, httpClient.Transport)
 
 	// Verify timeout
-	assertEqual(t, 15*time.Second, httpClient.Timeout)
+	assertEqual(t, 15*time.Second, attemptTimeout(t, httpClient))
 
 	// Test the transport is a retry transport
 	if retryTrans, ok := httpClient.Transport.(*retryTransport); ok {
@@ -310,3 +320,655 @@ func TestClientBuilder_Build_WithProxy(t *testing.T) {
 		}
 	})
 }
+
+func TestClientBuilder_BuildE(t *testing.T) {
+	t.Run("Valid settings", func(t *testing.T) {
+		client, err := NewClientBuilder().
+			WithMaxIdleConns(50).
+			WithTimeout(10 * time.Second).
+			WithMaxRetries(5).
+			BuildE()
+		if err != nil {
+			t.Fatalf("BuildE() failed: %v", err)
+		}
+		assertEqual(t, 10*time.Second, attemptTimeout(t, client))
+	})
+
+	t.Run("Out of range settings", func(t *testing.T) {
+		builder := NewClientBuilder().
+			WithMaxIdleConns(0).
+			WithTimeout(time.Hour).
+			WithMaxRetries(-1).
+			WithRetryStrategy(Strategy("unknown")).
+			WithMaxHeaderBytes(-1)
+
+		client, err := builder.BuildE()
+		if err == nil {
+			t.Fatal("Expected an error for out of range settings")
+		}
+		if client != nil {
+			t.Error("Expected no client when BuildE fails")
+		}
+		for _, want := range []string{
+			"max idle connections 0 out of range [1, 200]",
+			"timeout 1h0m0s out of range",
+			"max retries -1 out of range",
+			`invalid retry strategy "unknown"`,
+			"max header bytes -1 cannot be negative",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("Expected error containing %q, got %v", want, err)
+			}
+		}
+
+		// Build stays lenient and substitutes the defaults
+		assertEqual(t, DefaultTimeout, attemptTimeout(t, builder.Build()))
+	})
+}
+
+func TestClientBuilder_BuildE_Proxy(t *testing.T) {
+	t.Run("Valid proxy", func(t *testing.T) {
+		for _, proxyURL := range []string{"http://proxy.example.com:8080", "https://proxy.example.com", "socks5://127.0.0.1:1080"} {
+			client, err := NewClientBuilder().WithProxy(proxyURL).BuildE()
+			if err != nil {
+				t.Fatalf("BuildE() failed for %q: %v", proxyURL, err)
+			}
+			assertNotNil(t, baseTransport(t, client).Proxy)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		proxyURL string
+		wantErr  string
+	}{
+		{name: "Unparsable URL", proxyURL: "://invalid-url", wantErr: "invalid proxy URL"},
+		{name: "Missing scheme", proxyURL: "proxy.example.com:8080", wantErr: "scheme must be http, https, socks5 or socks5h"},
+		{name: "Unsupported scheme", proxyURL: "ftp://proxy.example.com", wantErr: "scheme must be"},
+		{name: "Missing host", proxyURL: "http://", wantErr: "missing host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClientBuilder().WithProxy(tt.proxyURL).BuildE()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if client != nil {
+				t.Error("Expected no client when BuildE fails")
+			}
+		})
+	}
+}
+
+// baseTransport returns the *http.Transport wrapped by the retry transport of a built client.
+func baseTransport(t *testing.T, client *http.Client) *http.Transport {
+	t.Helper()
+
+	rt, ok := client.Transport.(*retryTransport)
+	if !ok {
+		t.Fatalf("Expected *retryTransport, got %T", client.Transport)
+	}
+
+	transport, ok := rt.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport, got %T", rt.Transport)
+	}
+
+	return transport
+}
+
+// attemptTimeout returns the per-attempt timeout of a built client, which replaces
+// http.Client.Timeout so that the timeout does not span all retries.
+func attemptTimeout(t *testing.T, client *http.Client) time.Duration {
+	t.Helper()
+
+	if client.Timeout != 0 {
+		t.Errorf("Expected no http.Client.Timeout spanning all attempts, got %v", client.Timeout)
+	}
+
+	rt, ok := client.Transport.(*retryTransport)
+	if !ok {
+		t.Fatalf("Expected *retryTransport, got %T", client.Transport)
+	}
+
+	return rt.perAttemptTimeout
+}
+
+func TestClientBuilder_WithTLSServerName(t *testing.T) {
+	t.Run("Not configured by default", func(t *testing.T) {
+		transport := baseTransport(t, NewClientBuilder().Build())
+
+		if transport.TLSClientConfig != nil {
+			t.Error("Expected no TLS client config by default")
+		}
+	})
+
+	t.Run("Sets ServerName on the TLS client config", func(t *testing.T) {
+		transport := baseTransport(t, NewClientBuilder().WithTLSServerName("api.example.com").Build())
+
+		if transport.TLSClientConfig == nil {
+			t.Fatal("Expected TLS client config to be created")
+		}
+
+		assertEqual(t, "api.example.com", transport.TLSClientConfig.ServerName)
+	})
+
+	t.Run("Used to verify the server certificate", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		rootCAs := x509.NewCertPool()
+		rootCAs.AddCert(server.Certificate())
+
+		// The httptest certificate is valid for "example.com" but not for other names
+		for serverName, expectSuccess := range map[string]bool{"example.com": true, "other.test": false} {
+			client := NewClientBuilder().
+				WithTLSServerName(serverName).
+				WithMaxRetries(1).
+				Build()
+			baseTransport(t, client).TLSClientConfig.RootCAs = rootCAs
+
+			resp, err := client.Get(server.URL)
+			if expectSuccess {
+				if err != nil {
+					t.Fatalf("Expected request with ServerName %q to succeed, got %v", serverName, err)
+				}
+				resp.Body.Close()
+			} else if err == nil {
+				resp.Body.Close()
+				t.Errorf("Expected certificate verification error for ServerName %q", serverName)
+			}
+		}
+	})
+
+	t.Run("HTTP/2 is still negotiated", func(t *testing.T) {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		server.EnableHTTP2 = true
+		server.StartTLS()
+		defer server.Close()
+
+		rootCAs := x509.NewCertPool()
+		rootCAs.AddCert(server.Certificate())
+
+		client := NewClientBuilder().WithTLSServerName("example.com").Build()
+		baseTransport(t, client).TLSClientConfig.RootCAs = rootCAs
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		resp.Body.Close()
+
+		assertEqual(t, 2, resp.ProtoMajor)
+	})
+
+	t.Run("Generic client option", func(t *testing.T) {
+		client := NewGenericClient[User](WithTLSServerName[User]("api.example.com"))
+		transport := baseTransport(t, client.httpClient.(*http.Client))
+
+		assertEqual(t, "api.example.com", transport.TLSClientConfig.ServerName)
+	})
+}
+
+func TestClientBuilder_WithProxyConnectHeader(t *testing.T) {
+	header := http.Header{"Proxy-Authorization": {"Basic dXNlcjpwYXNz"}, "X-Route": {"eu-west"}}
+
+	clients := map[string]*http.Client{
+		"client builder": NewClientBuilder().WithProxyConnectHeader(header).Build(),
+		"generic client": NewGenericClient[User](WithProxyConnectHeader[User](header)).httpClient.(*http.Client),
+		"retry client":   NewHTTPRetryClient(WithProxyConnectHeaderRetry(header)),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			assertEqual(t, header, baseTransport(t, client).ProxyConnectHeader)
+		})
+	}
+
+	t.Run("Header is copied", func(t *testing.T) {
+		original := http.Header{"X-Route": {"eu-west"}}
+		builder := NewClientBuilder().WithProxyConnectHeader(original)
+		original.Set("X-Route", "us-east")
+
+		assertEqual(t, "eu-west", baseTransport(t, builder.Build()).ProxyConnectHeader.Get("X-Route"))
+	})
+
+	t.Run("Rejects CR and LF", func(t *testing.T) {
+		invalid := http.Header{"X-Route": {"eu-west\r\nX-Injected: 1"}}
+
+		transport := baseTransport(t, NewClientBuilder().WithProxyConnectHeader(invalid).Build())
+		if transport.ProxyConnectHeader != nil {
+			t.Errorf("Expected invalid CONNECT header to be ignored, got %v", transport.ProxyConnectHeader)
+		}
+
+		if transport := baseTransport(t, NewHTTPRetryClient(WithProxyConnectHeaderRetry(invalid))); transport.ProxyConnectHeader != nil {
+			t.Errorf("Expected invalid CONNECT header to be ignored by the retry client, got %v", transport.ProxyConnectHeader)
+		}
+	})
+}
+
+func TestClientBuilder_WithMinTLSVersion(t *testing.T) {
+	clients := map[string]*http.Client{
+		"client builder": NewClientBuilder().WithMinTLSVersion(tls.VersionTLS12).Build(),
+		"generic client": NewGenericClient[User](WithMinTLSVersion[User](tls.VersionTLS12)).httpClient.(*http.Client),
+		"retry client":   NewHTTPRetryClient(WithMinTLSVersionRetry(tls.VersionTLS12)),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			transport := baseTransport(t, client)
+			if transport.TLSClientConfig == nil {
+				t.Fatal("Expected TLS client config to be created")
+			}
+
+			assertEqual(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+		})
+	}
+
+	t.Run("Combined with TLS server name", func(t *testing.T) {
+		transport := baseTransport(t, NewClientBuilder().
+			WithTLSServerName("api.example.com").
+			WithMinTLSVersion(tls.VersionTLS13).
+			Build())
+
+		assertEqual(t, "api.example.com", transport.TLSClientConfig.ServerName)
+		assertEqual(t, uint16(tls.VersionTLS13), transport.TLSClientConfig.MinVersion)
+	})
+
+	t.Run("Invalid version is ignored", func(t *testing.T) {
+		if transport := baseTransport(t, NewClientBuilder().WithMinTLSVersion(0x0200).Build()); transport.TLSClientConfig != nil {
+			t.Errorf("Expected no TLS client config for an invalid version, got %+v", transport.TLSClientConfig)
+		}
+	})
+
+	t.Run("Retry client does not mutate the default transport", func(t *testing.T) {
+		NewHTTPRetryClient(WithMinTLSVersionRetry(tls.VersionTLS13))
+
+		if defaultTransport := http.DefaultTransport.(*http.Transport); defaultTransport.TLSClientConfig != nil &&
+			defaultTransport.TLSClientConfig.MinVersion == tls.VersionTLS13 {
+			t.Error("Expected http.DefaultTransport to be left unchanged")
+		}
+	})
+}
+
+func TestClientBuilder_WithBaseTransport(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	shared := &http.Transport{MaxIdleConnsPerHost: 7}
+
+	first := NewClientBuilder().WithBaseTransport(shared).WithTimeout(3 * time.Second).Build()
+	second := NewClientBuilder().WithBaseTransport(shared).Build()
+
+	for name, client := range map[string]*http.Client{"first": first, "second": second} {
+		if rt := client.Transport.(*retryTransport); rt.Transport != shared {
+			t.Errorf("%s client: expected the shared transport under the retry layer, got %T", name, rt.Transport)
+		}
+	}
+	assertEqual(t, 3*time.Second, attemptTimeout(t, first))
+
+	// Retries still apply on top of the custom transport
+	first.Transport.(*retryTransport).RetryStrategy = FixedDelay(time.Millisecond)
+	resp, err := first.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	resp.Body.Close()
+	assertEqual(t, http.StatusOK, resp.StatusCode)
+	assertEqual(t, int32(2), atomic.LoadInt32(&attempts))
+
+	t.Run("Transport options are ignored with a warning", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+		client := NewClientBuilder().
+			WithBaseTransport(shared).
+			WithProxy("http://proxy.example.com:8080").
+			WithLogger(logger).
+			Build()
+
+		if client.Transport.(*retryTransport).Transport != shared {
+			t.Error("Expected the custom transport to be used")
+		}
+		if shared.Proxy != nil {
+			t.Error("Expected the custom transport not to be modified")
+		}
+		if !strings.Contains(logBuf.String(), "Custom base transport provided") {
+			t.Errorf("Expected a warning about ignored options, got %q", logBuf.String())
+		}
+	})
+
+	t.Run("Disabled compression is ignored with a warning", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+		NewClientBuilder().
+			WithBaseTransport(shared).
+			WithDisableCompression(true).
+			WithLogger(logger).
+			Build()
+
+		if shared.DisableCompression {
+			t.Error("Expected the custom transport not to be modified")
+		}
+		if !strings.Contains(logBuf.String(), "compression options ignored") {
+			t.Errorf("Expected a warning about ignored options, got %q", logBuf.String())
+		}
+	})
+
+	t.Run("Generic client forwards WithTransport", func(t *testing.T) {
+		client := NewGenericClient[User](WithTransport[User](shared)).httpClient.(*http.Client)
+		if client.Transport.(*retryTransport).Transport != shared {
+			t.Error("Expected the generic client to use the custom transport")
+		}
+	})
+}
+
+func TestWithMaxHeaderBytes_Options(t *testing.T) {
+	clients := map[string]*http.Client{
+		"client builder": NewClientBuilder().WithMaxHeaderBytes(4096).Build(),
+		"generic client": NewGenericClient[User](WithMaxHeaderBytes[User](4096)).httpClient.(*http.Client),
+		"retry client":   NewHTTPRetryClient(WithMaxHeaderBytesRetry(4096)),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			assertEqual(t, int64(4096), baseTransport(t, client).MaxResponseHeaderBytes)
+		})
+	}
+
+	t.Run("Oversized headers are rejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/large" {
+				w.Header().Set("X-Large", strings.Repeat("a", 8192))
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		client := NewClientBuilder().WithMaxHeaderBytes(4096).WithMaxRetries(1).Build()
+
+		resp, err := client.Get(server.URL + "/small")
+		if err != nil {
+			t.Fatalf("Expected small headers to be accepted, got %v", err)
+		}
+		resp.Body.Close()
+
+		if _, err := client.Get(server.URL + "/large"); err == nil || !strings.Contains(err.Error(), "headers exceeded") {
+			t.Errorf("Expected a header size error, got %v", err)
+		}
+	})
+
+	t.Run("Negative values are ignored", func(t *testing.T) {
+		assertEqual(t, int64(0), baseTransport(t, NewClientBuilder().WithMaxHeaderBytes(-1).Build()).MaxResponseHeaderBytes)
+	})
+}
+
+func TestWithLocalAddr_Options(t *testing.T) {
+	localAddr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		w.Header().Set("X-Remote-IP", host)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	clients := map[string]*http.Client{
+		"client builder": NewClientBuilder().WithLocalAddr(localAddr).Build(),
+		"generic client": NewGenericClient[User](WithLocalAddr[User](localAddr)).httpClient.(*http.Client),
+		"retry client":   NewHTTPRetryClient(WithLocalAddrRetry(localAddr)),
+		"with DNS cache": NewClientBuilder().WithLocalAddr(localAddr).WithDNSCache(time.Minute).Build(),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			if baseTransport(t, client).DialContext == nil {
+				t.Fatal("Expected a custom DialContext")
+			}
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			resp.Body.Close()
+
+			assertEqual(t, "127.0.0.1", resp.Header.Get("X-Remote-IP"))
+		})
+	}
+
+	t.Run("Unassigned address fails to dial", func(t *testing.T) {
+		// 192.0.2.0/24 is reserved for documentation and not assigned to local interfaces
+		client := NewClientBuilder().
+			WithLocalAddr(&net.TCPAddr{IP: net.IPv4(192, 0, 2, 1)}).
+			WithMaxRetries(1).
+			WithRetryStrategyFunc(func(int) time.Duration { return time.Millisecond }).
+			Build()
+
+		if resp, err := client.Get(server.URL); err == nil {
+			resp.Body.Close()
+			t.Error("Expected the dial from an unassigned local address to fail")
+		}
+	})
+}
+
+func TestWithDisableCompression_Options(t *testing.T) {
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+	}))
+	defer server.Close()
+
+	clients := map[string]*http.Client{
+		"client builder": NewClientBuilder().WithDisableCompression(true).Build(),
+		"generic client": NewGenericClient[User](WithDisableCompression[User](true)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			assertTrue(t, baseTransport(t, client).DisableCompression)
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Get() failed: %v", err)
+			}
+			resp.Body.Close()
+			assertEqual(t, "", acceptEncoding)
+		})
+	}
+
+	t.Run("Compression enabled by default", func(t *testing.T) {
+		client := NewClientBuilder().Build()
+		assertEqual(t, false, baseTransport(t, client).DisableCompression)
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		resp.Body.Close()
+		assertEqual(t, "gzip", acceptEncoding)
+	})
+}
+
+func TestWithPhaseTimeouts_Options(t *testing.T) {
+	timeouts := PhaseTimeouts{
+		Connect:        2 * time.Second,
+		TLSHandshake:   3 * time.Second,
+		Write:          5 * time.Second,
+		ResponseHeader: 4 * time.Second,
+		Attempt:        20 * time.Second,
+		Overall:        time.Minute,
+	}
+
+	clients := map[string]*http.Client{
+		"client builder": NewClientBuilder().WithPhaseTimeouts(timeouts).Build(),
+		"generic client": NewGenericClient[User](WithPhaseTimeouts[User](timeouts), WithTimeout[User](time.Second)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			transport := baseTransport(t, client)
+			if transport.DialContext == nil {
+				t.Error("Expected a custom DialContext for the connect timeout")
+			}
+			assertEqual(t, 3*time.Second, transport.TLSHandshakeTimeout)
+			assertEqual(t, 4*time.Second, transport.ResponseHeaderTimeout)
+			assertEqual(t, 20*time.Second, attemptTimeout(t, client))
+			assertEqual(t, time.Minute, client.Transport.(*retryTransport).maxResponseTime)
+		})
+	}
+
+	t.Run("Zero fields keep other settings", func(t *testing.T) {
+		client := NewClientBuilder().
+			WithTimeout(7 * time.Second).
+			WithPhaseTimeouts(PhaseTimeouts{ResponseHeader: time.Second}).
+			Build()
+
+		transport := baseTransport(t, client)
+		assertEqual(t, DefaultTLSHandshakeTimeout, transport.TLSHandshakeTimeout)
+		assertEqual(t, time.Second, transport.ResponseHeaderTimeout)
+		assertTrue(t, transport.DialContext == nil)
+		assertEqual(t, 7*time.Second, attemptTimeout(t, client))
+	})
+
+	t.Run("Per-attempt timeout takes precedence", func(t *testing.T) {
+		client := NewClientBuilder().
+			WithPhaseTimeouts(PhaseTimeouts{Attempt: 20 * time.Second}).
+			WithPerAttemptTimeout(500 * time.Millisecond).
+			Build()
+
+		assertEqual(t, 500*time.Millisecond, attemptTimeout(t, client))
+	})
+
+	t.Run("Write timeout is enforced", func(t *testing.T) {
+		// A peer that accepts connections but never reads from them
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Listen() failed: %v", err)
+		}
+		defer listener.Close()
+
+		go func() {
+			var conns []net.Conn
+			defer func() {
+				for _, conn := range conns {
+					conn.Close()
+				}
+			}()
+
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				conns = append(conns, conn)
+			}
+		}()
+
+		client := NewClientBuilder().
+			WithPhaseTimeouts(PhaseTimeouts{Write: 100 * time.Millisecond}).
+			WithMaxRetries(1).
+			WithRetryStrategyFunc(func(int) time.Duration { return time.Millisecond }).
+			Build()
+
+		start := time.Now()
+		_, err = client.Post("http://"+listener.Addr().String(), "application/octet-stream", bytes.NewReader(make([]byte, 64<<20)))
+		if err == nil || !strings.Contains(err.Error(), "i/o timeout") {
+			t.Fatalf("Expected a write timeout, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 3*time.Second {
+			t.Errorf("Expected the request to fail quickly, took %v", elapsed)
+		}
+	})
+
+	t.Run("Response header timeout is enforced", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		}))
+		defer server.Close()
+
+		client := NewClientBuilder().
+			WithPhaseTimeouts(PhaseTimeouts{ResponseHeader: 50 * time.Millisecond}).
+			WithMaxRetries(1).
+			WithRetryStrategyFunc(func(int) time.Duration { return time.Millisecond }).
+			Build()
+
+		start := time.Now()
+		_, err := client.Get(server.URL)
+		if err == nil || !strings.Contains(err.Error(), "timeout awaiting response headers") {
+			t.Fatalf("Expected a response header timeout, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected the request to fail quickly, took %v", elapsed)
+		}
+	})
+}
+
+func TestWithUnixSocket_Options(t *testing.T) {
+	// Keep the socket path short; unix socket paths are limited to about 100 bytes
+	dir, err := os.MkdirTemp("", "httpx")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	socketPath := filepath.Join(dir, "api.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("Unix sockets not supported: %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Path", r.URL.RequestURI())
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id":1,"name":"Ada"}`))
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	clients := map[string]*http.Client{
+		"client builder": NewClientBuilder().WithUnixSocket(socketPath).Build(),
+		"generic client": NewGenericClient[User](WithUnixSocket[User](socketPath)).httpClient.(*http.Client),
+		"retry client":   NewHTTPRetryClient(WithUnixSocketRetry(socketPath)),
+		"with DNS cache": NewClientBuilder().WithUnixSocket(socketPath).WithDNSCache(time.Minute).Build(),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			resp, err := client.Get("http://unix/v1/users?id=1")
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			resp.Body.Close()
+
+			assertEqual(t, http.StatusOK, resp.StatusCode)
+			assertEqual(t, "/v1/users?id=1", resp.Header.Get("X-Path"))
+		})
+	}
+
+	t.Run("Generic client decodes responses", func(t *testing.T) {
+		client := NewGenericClient[User](WithUnixSocket[User](socketPath))
+
+		resp, err := client.Get("http://localhost/users/1")
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		assertEqual(t, "Ada", resp.Data.Name)
+	})
+}
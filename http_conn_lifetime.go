@@ -0,0 +1,115 @@
+package httpx
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// withConnLifetime returns a dial function wrapping the connections of dial, so that
+// they are retired once they are older than lifetime.
+func withConnLifetime(dial dialContextFunc, lifetime time.Duration) dialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		lc := &lifetimeConn{Conn: conn}
+		lc.timer = time.AfterFunc(lifetime, lc.expire)
+
+		return lc, nil
+	}
+}
+
+// lifetimeConn is a connection that is closed once it has expired and is idle in the
+// connection pool. Requests in flight are never interrupted: an expired connection in use
+// is closed when it is returned to the pool instead. Whether the connection is in use is
+// tracked by the retry transport with the hooks of connLifetimeTrace.
+type lifetimeConn struct {
+	net.Conn
+	timer *time.Timer
+
+	mu      sync.Mutex
+	inUse   int  // Requests using the connection
+	expired bool // The lifetime has elapsed
+}
+
+// expire marks the connection as expired and closes it if it is idle.
+func (c *lifetimeConn) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expired = true
+	if c.inUse == 0 {
+		_ = c.Conn.Close()
+	}
+}
+
+// acquire records that a request got the connection.
+func (c *lifetimeConn) acquire() {
+	c.mu.Lock()
+	c.inUse++
+	c.mu.Unlock()
+}
+
+// release records that a request returned the connection to the pool, and closes it if
+// it has expired and no other request uses it.
+func (c *lifetimeConn) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.inUse > 0 {
+		c.inUse--
+	}
+
+	if c.expired && c.inUse == 0 {
+		_ = c.Conn.Close()
+	}
+}
+
+func (c *lifetimeConn) Close() error {
+	c.timer.Stop()
+
+	return c.Conn.Close()
+}
+
+// connLifetimeTrace adds to trace the hooks tracking when connections dialed with
+// withConnLifetime are taken from and returned to the pool. HTTP/2 connections, which are
+// shared by concurrent requests, are never returned to the pool and are not retired.
+func connLifetimeTrace(trace *httptrace.ClientTrace) {
+	var conn atomic.Pointer[lifetimeConn]
+
+	gotConn := trace.GotConn
+	trace.GotConn = func(info httptrace.GotConnInfo) {
+		if gotConn != nil {
+			gotConn(info)
+		}
+
+		if lc, ok := asLifetimeConn(info.Conn); ok {
+			lc.acquire()
+			conn.Store(lc)
+		}
+	}
+
+	trace.PutIdleConn = func(error) {
+		if lc := conn.Swap(nil); lc != nil {
+			lc.release()
+		}
+	}
+}
+
+// asLifetimeConn returns the connection dialed with withConnLifetime that conn wraps, if any.
+func asLifetimeConn(conn net.Conn) (*lifetimeConn, bool) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+
+	lc, ok := conn.(*lifetimeConn)
+
+	return lc, ok
+}
@@ -0,0 +1,144 @@
+package httpx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithMaxConnLifetime(t *testing.T) {
+	var dials atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(150 * time.Millisecond)
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			dials.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	get := func(t *testing.T, client *http.Client) {
+		t.Helper()
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	t.Run("Expired connections are replaced", func(t *testing.T) {
+		dials.Store(0)
+		client := NewClientBuilder().WithMaxConnLifetime(100 * time.Millisecond).Build()
+
+		// Connections are reused within their lifetime
+		get(t, client)
+		get(t, client)
+		assertEqual(t, int32(1), dials.Load())
+
+		time.Sleep(150 * time.Millisecond)
+		get(t, client)
+		assertEqual(t, int32(2), dials.Load())
+	})
+
+	t.Run("Requests in flight are not interrupted", func(t *testing.T) {
+		dials.Store(0)
+		client := NewClientBuilder().WithMaxConnLifetime(100 * time.Millisecond).Build()
+
+		// The body is written after the connection expired, once the server asks for it
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/slow", strings.NewReader("payload"))
+		req.Header.Set("Expect", "100-continue")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do() failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assertEqual(t, "payload", string(body))
+
+		// The expired connection is closed once the request is done
+		get(t, client)
+		assertEqual(t, int32(2), dials.Load())
+	})
+
+	t.Run("Bodies that cannot be replayed", func(t *testing.T) {
+		dials.Store(0)
+		client := NewClientBuilder().WithMaxConnLifetime(100 * time.Millisecond).Build()
+
+		get(t, client)
+		time.Sleep(150 * time.Millisecond)
+
+		// Without GetBody, the request could not be sent again on a new connection
+		req, _ := http.NewRequest(http.MethodPost, server.URL, io.NopCloser(strings.NewReader("payload")))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do() failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assertEqual(t, "payload", string(body))
+		assertEqual(t, int32(2), dials.Load())
+	})
+
+	t.Run("No limit by default", func(t *testing.T) {
+		dials.Store(0)
+		client := NewClientBuilder().Build()
+
+		get(t, client)
+		time.Sleep(150 * time.Millisecond)
+		get(t, client)
+		assertEqual(t, int32(1), dials.Load())
+	})
+
+	t.Run("Generic client", func(t *testing.T) {
+		dials.Store(0)
+		client := NewGenericClient[User](WithMaxConnLifetime[User](100 * time.Millisecond)).httpClient.(*http.Client)
+
+		get(t, client)
+		time.Sleep(150 * time.Millisecond)
+		get(t, client)
+		assertEqual(t, int32(2), dials.Load())
+	})
+}
+
+func TestWithMaxConnLifetime_HTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(150 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	client := NewClientBuilder().WithMaxConnLifetime(100 * time.Millisecond).Build()
+	baseTransport(t, client).TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+
+	// Shared HTTP/2 connections are not retired, even when requests outlive them
+	for range 2 {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		assertEqual(t, 2, resp.ProtoMajor)
+	}
+}
@@ -0,0 +1,95 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Credential authenticates a request by setting headers on it, e.g. Authorization.
+// Credentials are applied by RequestBuilder.Build after all other headers are set, so
+// several of them can be layered, such as an Authorization header and an API key for
+// a gateway in front of the API.
+type Credential interface {
+	Apply(req *http.Request)
+}
+
+// credentialValidator is implemented by the built-in credentials to report invalid
+// values when they are passed to WithCredentials rather than sending a broken header.
+type credentialValidator interface {
+	validate() error
+}
+
+// BasicAuth is a Credential that sets the Authorization header for basic authentication.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Apply sets the Authorization header of req to the basic authentication credentials.
+func (c BasicAuth) Apply(req *http.Request) {
+	req.Header.Set("Authorization", "Basic "+basicAuth(c.Username, c.Password))
+}
+
+func (c BasicAuth) validate() error {
+	if c.Username == "" {
+		return fmt.Errorf("username for basic auth cannot be empty")
+	}
+
+	if c.Password == "" {
+		return fmt.Errorf("password for basic auth cannot be empty")
+	}
+
+	return nil
+}
+
+// BearerToken is a Credential that sets the Authorization header for bearer token authentication.
+type BearerToken string
+
+// Apply sets the Authorization header of req to the bearer token.
+func (t BearerToken) Apply(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+}
+
+func (t BearerToken) validate() error {
+	if t == "" {
+		return fmt.Errorf("bearer token cannot be empty")
+	}
+
+	if strings.ContainsAny(string(t), "\r\n") {
+		return fmt.Errorf("bearer token cannot contain control characters (\\r, \\n)")
+	}
+
+	return nil
+}
+
+// APIKeyHeader is a Credential that sets an API key in a header, e.g. "X-Api-Key".
+type APIKeyHeader struct {
+	Header string
+	Key    string
+}
+
+// Apply sets the API key header of req.
+func (c APIKeyHeader) Apply(req *http.Request) {
+	req.Header.Set(c.Header, c.Key)
+}
+
+func (c APIKeyHeader) validate() error {
+	if c.Header == "" {
+		return fmt.Errorf("API key header cannot be empty")
+	}
+
+	if strings.ContainsAny(c.Header, " \t\n\r") {
+		return fmt.Errorf("invalid API key header format: '%s' (contains whitespace)", c.Header)
+	}
+
+	if c.Key == "" {
+		return fmt.Errorf("API key for header '%s' cannot be empty", c.Header)
+	}
+
+	if strings.ContainsAny(c.Key, "\r\n") {
+		return fmt.Errorf("API key for header '%s' cannot contain control characters (\\r, \\n)", c.Header)
+	}
+
+	return nil
+}
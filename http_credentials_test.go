@@ -0,0 +1,74 @@
+package httpx
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// signatureCredential is a custom Credential setting a header derived from the request.
+type signatureCredential struct{}
+
+func (signatureCredential) Apply(req *http.Request) {
+	req.Header.Set("X-Signature", req.Method+" "+req.URL.Path)
+}
+
+func TestRequestBuilder_WithCredentials(t *testing.T) {
+	t.Run("Layered credentials", func(t *testing.T) {
+		req, err := NewRequestBuilder("https://api.example.com").
+			WithMethodGET().
+			WithPath("/users").
+			WithCredentials(BearerToken("token-123"), APIKeyHeader{Header: "X-Api-Key", Key: "key-456"}).
+			WithCredentials(signatureCredential{}).
+			Build()
+		if err != nil {
+			t.Fatalf("Build() failed: %v", err)
+		}
+
+		assertEqual(t, "Bearer token-123", req.Header.Get("Authorization"))
+		assertEqual(t, "key-456", req.Header.Get("X-Api-Key"))
+		assertEqual(t, "GET /users", req.Header.Get("X-Signature"))
+	})
+
+	t.Run("Later credentials override earlier headers", func(t *testing.T) {
+		req, err := NewRequestBuilder("https://api.example.com").
+			WithMethodGET().
+			WithBearerAuth("builder-token").
+			WithCredentials(BasicAuth{Username: "user", Password: "pass"}).
+			Build()
+		if err != nil {
+			t.Fatalf("Build() failed: %v", err)
+		}
+
+		username, password, ok := req.BasicAuth()
+		assertTrue(t, ok)
+		assertEqual(t, "user", username)
+		assertEqual(t, "pass", password)
+	})
+
+	tests := []struct {
+		name    string
+		cred    Credential
+		wantErr string
+	}{
+		{name: "Nil credential", cred: nil, wantErr: "credential cannot be nil"},
+		{name: "Empty bearer token", cred: BearerToken(""), wantErr: "bearer token cannot be empty"},
+		{name: "Bearer token with newline", cred: BearerToken("a\r\nb"), wantErr: "control characters"},
+		{name: "Basic auth without password", cred: BasicAuth{Username: "user"}, wantErr: "password for basic auth cannot be empty"},
+		{name: "API key without header", cred: APIKeyHeader{Key: "key"}, wantErr: "API key header cannot be empty"},
+		{name: "API key header with whitespace", cred: APIKeyHeader{Header: "X Api Key", Key: "key"}, wantErr: "contains whitespace"},
+		{name: "Empty API key", cred: APIKeyHeader{Header: "X-Api-Key"}, wantErr: "API key for header 'X-Api-Key' cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRequestBuilder("https://api.example.com").
+				WithMethodGET().
+				WithCredentials(tt.cred).
+				Build()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
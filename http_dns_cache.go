@@ -0,0 +1,115 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// dialContextFunc is the signature of http.Transport.DialContext.
+type dialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// dnsCache caches the addresses resolved for a host for a fixed TTL, so that
+// clients repeatedly connecting to the same hosts avoid a DNS lookup per connection.
+// It is safe for concurrent use.
+type dnsCache struct {
+	ttl      time.Duration
+	resolver *net.Resolver
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]dnsCacheEntry
+}
+
+// dnsCacheEntry holds the resolved addresses of a host and when they expire.
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// newDNSCache creates a DNS cache keeping resolved addresses for ttl,
+// using the standard library resolver.
+func newDNSCache(ttl time.Duration) *dnsCache {
+	return &dnsCache{
+		ttl:      ttl,
+		resolver: net.DefaultResolver,
+		now:      time.Now,
+		entries:  make(map[string]dnsCacheEntry),
+	}
+}
+
+// lookup returns the addresses of host, resolving them only when they are not
+// cached or have expired. Failed lookups are not cached.
+func (c *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+
+	if ok && c.now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	// The lock is not held during the lookup, so concurrent misses for the same
+	// host may resolve it more than once; the last result wins.
+	addrs, err := c.resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[host] = dnsCacheEntry{addrs: addrs, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return addrs, nil
+}
+
+// dialContext returns a DialContext function that resolves host names through
+// the cache and dials the resolved addresses in order with dial until one succeeds.
+// Addresses that are already IP literals are dialed directly.
+func (c *dnsCache) dialContext(dial dialContextFunc) dialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+
+		addrs, err := c.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var dialErr error
+		for _, ip := range addrs {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+
+			conn, err := dial(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+
+			dialErr = err
+		}
+
+		if dialErr == nil {
+			dialErr = fmt.Errorf("no addresses found for host %s", host)
+		}
+
+		return nil, dialErr
+	}
+}
+
+// newDefaultDialer returns a net.Dialer with the same settings as http.DefaultTransport.
+func newDefaultDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+}
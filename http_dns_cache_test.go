@@ -0,0 +1,98 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDNSCache_DialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("SplitHostPort() failed: %v", err)
+	}
+
+	now := time.Now()
+	cache := newDNSCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	// A host that only exists in the cache proves that cached addresses are used
+	cache.entries["api.internal.test"] = dnsCacheEntry{addrs: []string{"127.0.0.1"}, expires: now.Add(time.Minute)}
+
+	var dialed []string
+	dial := cache.dialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		return newDefaultDialer().DialContext(ctx, network, addr)
+	})
+
+	conn, err := dial(context.Background(), "tcp", net.JoinHostPort("api.internal.test", port))
+	if err != nil {
+		t.Fatalf("Dial via cached host failed: %v", err)
+	}
+	conn.Close()
+
+	conn, err = dial(context.Background(), "tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial via IP literal failed: %v", err)
+	}
+	conn.Close()
+
+	expected := []string{net.JoinHostPort("127.0.0.1", port), server.Listener.Addr().String()}
+	assertEqual(t, expected, dialed)
+
+	// Expired entries are resolved again
+	now = now.Add(2 * time.Minute)
+	if _, err := dial(context.Background(), "tcp", net.JoinHostPort("api.internal.test", port)); err == nil {
+		t.Error("Expected the expired entry for an unresolvable host to be looked up again and fail")
+	}
+
+	t.Run("Respects context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		cache.entries["api.internal.test"] = dnsCacheEntry{addrs: []string{"127.0.0.1"}, expires: now.Add(time.Minute)}
+		if _, err := dial(ctx, "tcp", net.JoinHostPort("api.internal.test", port)); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestWithDNSCache_Options(t *testing.T) {
+	clients := map[string]*http.Client{
+		"client builder": NewClientBuilder().WithDNSCache(time.Minute).Build(),
+		"generic client": NewGenericClient[User](WithDNSCache[User](time.Minute)).httpClient.(*http.Client),
+		"retry client":   NewHTTPRetryClient(WithDNSCacheRetry(time.Minute)),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			if baseTransport(t, client).DialContext == nil {
+				t.Fatal("Expected a DialContext resolving through the DNS cache")
+			}
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Get() failed: %v", err)
+			}
+			resp.Body.Close()
+		})
+	}
+
+	if baseTransport(t, NewClientBuilder().Build()).DialContext != nil {
+		t.Error("Expected no custom DialContext by default")
+	}
+}
@@ -0,0 +1,99 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrChecksumMismatch is returned by DownloadVerified when the downloaded content does not
+// match the expected checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// DownloadVerified downloads url to the file dest and verifies that the SHA-256 checksum of
+// the content matches sha256hex, the hex-encoded checksum, e.g. as published next to a
+// release artifact. The checksum is computed while the body streams to disk, into a temporary
+// file in the directory of dest that is renamed to dest only when the checksum matches, so dest
+// never holds partial or tampered content. On a mismatch the temporary file is deleted and an
+// error wrapping ErrChecksumMismatch is returned. It behaves like ExecuteRaw, including
+// progress reporting, and counts failures in Stats. Use DownloadVerifiedContext to pass a
+// context.
+func (c *GenericClient[T]) DownloadVerified(url, dest, sha256hex string) error {
+	return c.DownloadVerifiedContext(context.Background(), url, dest, sha256hex)
+}
+
+// DownloadVerifiedContext is like DownloadVerified with a context for the request.
+func (c *GenericClient[T]) DownloadVerifiedContext(ctx context.Context, url, dest, sha256hex string) error {
+	expected, err := hex.DecodeString(strings.TrimSpace(sha256hex))
+	if err != nil || len(expected) != sha256.Size {
+		return fmt.Errorf("invalid SHA-256 checksum %q: must be %d hex-encoded bytes", sha256hex, sha256.Size)
+	}
+
+	if dest == "" {
+		return fmt.Errorf("download destination cannot be empty")
+	}
+
+	if c.perRequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.perRequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create GET request: %w", err)
+	}
+
+	return c.executeRawWith(req, func(resp *http.Response) error {
+		return c.saveVerified(resp, url, dest, expected)
+	})
+}
+
+// saveVerified writes the body of resp to dest if its SHA-256 checksum is expected.
+func (c *GenericClient[T]) saveVerified(resp *http.Response, url, dest string, expected []byte) error {
+	if resp.StatusCode >= 400 {
+		return c.errorFromResponse(resp)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*.part")
+	if err != nil {
+		return fmt.Errorf("create temporary download file: %w", err)
+	}
+
+	// The temporary file is removed unless it was renamed to dest
+	renamed := false
+	defer func() {
+		if !renamed {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body); err != nil {
+		return fmt.Errorf("download %s: %w", url, err)
+	}
+
+	if actual := hash.Sum(nil); !bytes.Equal(actual, expected) {
+		return fmt.Errorf("%w: %s: expected SHA-256 %x, got %x", ErrChecksumMismatch, url, expected, actual)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write download file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return fmt.Errorf("move download file to %s: %w", dest, err)
+	}
+	renamed = true
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package httpx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenericClient_DownloadVerified(t *testing.T) {
+	artifact := strings.Repeat("release artifact\n", 1024)
+	sum := sha256.Sum256([]byte(artifact))
+	checksum := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifact":
+			_, _ = w.Write([]byte(artifact))
+		case "/tampered":
+			_, _ = w.Write([]byte(artifact + "malware"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[any]()
+
+	// leftovers reports the files in dir, which must hold no partial downloads
+	leftovers := func(t *testing.T, dir string) []string {
+		t.Helper()
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("ReadDir() failed: %v", err)
+		}
+
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+
+		return names
+	}
+
+	t.Run("Matching checksum", func(t *testing.T) {
+		dir := t.TempDir()
+		dest := filepath.Join(dir, "tool.tar.gz")
+
+		if err := client.DownloadVerified(server.URL+"/artifact", dest, strings.ToUpper(checksum)); err != nil {
+			t.Fatalf("DownloadVerified() failed: %v", err)
+		}
+
+		data, err := os.ReadFile(dest)
+		if err != nil {
+			t.Fatalf("ReadFile() failed: %v", err)
+		}
+		assertEqual(t, artifact, string(data))
+		assertEqual(t, []string{"tool.tar.gz"}, leftovers(t, dir))
+	})
+
+	t.Run("Checksum mismatch", func(t *testing.T) {
+		dir := t.TempDir()
+		dest := filepath.Join(dir, "tool.tar.gz")
+
+		err := client.DownloadVerified(server.URL+"/tampered", dest, checksum)
+		if !errors.Is(err, ErrChecksumMismatch) {
+			t.Fatalf("Expected ErrChecksumMismatch, got %v", err)
+		}
+		assertEqual(t, 0, len(leftovers(t, dir)))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		dir := t.TempDir()
+		dest := filepath.Join(dir, "tool.tar.gz")
+
+		err := client.DownloadVerified(server.URL+"/artifact", dest, "abc")
+		if err == nil || !strings.Contains(err.Error(), "invalid SHA-256 checksum") {
+			t.Errorf("Expected an invalid checksum error, got %v", err)
+		}
+
+		err = client.DownloadVerified(server.URL+"/missing", dest, checksum)
+		var apiErr *ErrorResponse
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected a 404 ErrorResponse, got %v", err)
+		}
+		assertEqual(t, 0, len(leftovers(t, dir)))
+	})
+}
@@ -1,15 +1,57 @@
 package httpx
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/http/httputil"
+	"net/url"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
+// ErrClientClosed is returned when a request is executed on a GenericClient after Close was called.
+var ErrClientClosed = errors.New("client is closed")
+
+// ErrUnexpectedContentType is returned when a successful response has a Content-Type
+// other than those configured with WithExpectedContentType.
+var ErrUnexpectedContentType = errors.New("unexpected content type")
+
+// ErrEmptyBody is returned when a successful response has no body to decode and empty
+// bodies are not allowed (see WithAllowEmptyBody).
+var ErrEmptyBody = errors.New("empty response body")
+
+// ErrPreflightFailed is returned when the preflight probe set with WithPreflight fails,
+// in which case the upload it guards is not sent.
+var ErrPreflightFailed = errors.New("preflight request failed")
+
+// ErrJSONNull is returned when a successful response sets a field that cannot hold null
+// to null and nulls are rejected (see WithRejectJSONNulls).
+var ErrJSONNull = errors.New("JSON null for non-nullable field")
+
+// DefaultMaxErrorBodyBytes is the default maximum number of bytes read from the body
+// of an error response (status code >= 400).
+const DefaultMaxErrorBodyBytes = 64 << 10
+
+// maxErrorBodyDrainBytes is the most bytes discarded from the rest of a truncated error
+// response body, so that its connection can be reused. Longer bodies are left unread and
+// their connection is closed.
+const maxErrorBodyDrainBytes = 256 << 10
+
 // HTTPClient is an interface that defines the methods required for making HTTP requests.
 // This allows for easier testing and mocking of HTTP requests in unit tests.
 type HTTPClient interface {
@@ -21,31 +63,226 @@ type HTTPClient interface {
 type GenericClient[T any] struct {
 	httpClient HTTPClient
 	// Configuration fields for building HTTP client
-	customClient          HTTPClient // If set, use this instead of building one
+	customClient          HTTPClient        // If set, use this instead of building one
+	transport             http.RoundTripper // If set, used as the transport of the built client, under the retry layer
 	maxIdleConns          *int
 	idleConnTimeout       *time.Duration
 	tlsHandshakeTimeout   *time.Duration
 	expectContinueTimeout *time.Duration
+	phaseTimeouts         *PhaseTimeouts // Timeouts of the phases of a request, overriding the individual ones
+	responseHeaderTimeout *time.Duration // Timeout of waiting for response headers
+	bodyReadTimeout       *time.Duration // Timeout of each read of the response body
 	maxIdleConnsPerHost   *int
 	timeout               *time.Duration
 	maxRetries            *int
 	retryBaseDelay        *time.Duration
 	retryMaxDelay         *time.Duration
+	retryMultiplier       *float64
 	retryStrategy         *Strategy
+	retryStrategyFunc     RetryStrategy // Custom strategy function (nil = use retryStrategy)
 	disableKeepAlive      *bool
-	proxyURL              *string      // Proxy URL (e.g., "http://proxy.example.com:8080")
-	logger                *slog.Logger // Optional logger (nil = no logging)
+	disableCompression    *bool
+	proxyURL              *string        // Proxy URL (e.g., "http://proxy.example.com:8080")
+	logger                *slog.Logger   // Optional logger (nil = no logging)
+	maxRetriesForStatus   map[int]int    // Per-status-code overrides of maxRetries
+	nonRetryableStatus    []int          // 5xx and 429 status codes that are never retried
+	deadlineHeader        *string        // Header carrying the remaining request deadline
+	attemptsHeader        *string        // Response header carrying the number of attempts
+	tlsServerName         *string        // TLS ServerName (SNI) override
+	proxyConnectHeader    http.Header    // Headers sent on proxy CONNECT requests
+	minTLSVersion         *uint16        // Minimum TLS version
+	dnsCacheTTL           *time.Duration // How long resolved host addresses are cached
+	maxConnLifetime       *time.Duration // Age after which pooled connections are not reused
+	maxHeaderBytes        *int64         // Limit on response header bytes
+	localAddr             net.Addr       // Local address outgoing connections are bound to
+	unixSocket            *string        // Path of a unix domain socket all connections are dialed to
+
+	retryBudgetRatio        *float64 // Retries allowed per request within the retry budget window
+	retryBudgetMinPerSecond int      // Retries per second always allowed by the retry budget
+
+	retryableError  func(error) bool // Decides whether transport errors are retried
+	maxResponseTime *time.Duration   // Bound on a request including all retries
+
+	absoluteMaxDelay  *time.Duration // Hard ceiling on every retry delay
+	retryAfterMaxWait *time.Duration // Longest Retry-After delay honored before giving up
+	respectRetryAfter *bool          // Lengthen retry delays to the Retry-After header
+
+	perAttemptTimeout *time.Duration // Bound on each attempt of a request
+
+	retryOnConnectionReset *bool // Whether connection resets are always retried
+
+	maxRetryBodyBytes *int // Largest body without GetBody buffered for retries
+
+	// Request configuration applied in the convenience methods (Get, Post, ...)
+	perRequestTimeout time.Duration // Timeout of each request including retries (0 = no timeout)
+
+	// Request configuration applied in Execute and ExecuteRaw
+	contextValues     []contextValue     // Values attached to every request context
+	baseQueryParams   url.Values         // Query parameters added to every request
+	requestEditors    []RequestEditor    // Functions run on every request before it is sent
+	requestValidators []RequestValidator // Functions that check every request before it is sent
+	preflightMethod   string             // Method of the probe sent before uploads (empty = no probe)
+	preflightPath     string             // Path of the probe (empty = the upload URL)
+	gzipResponses     bool               // Request gzip responses and decompress them
+
+	// Response configuration applied in Execute
+	responseHeaderValidators []ResponseHeaderValidator // Checks run on successful response headers
+	maxErrorBodyBytes        *int                      // Limit on bytes read from error response bodies
+	statusMessages           map[int]string            // Messages of error responses whose body provides none
+	decodeErrorValue         func(body []byte) any     // Decodes error bodies into the type set by WithErrorType
+	errorDecoder             ErrorDecoder              // Builds the errors of error responses (nil = ErrorResponse)
+	expectedContentTypes     []string                  // Media types accepted before decoding (empty = any)
+	rawBodyHooks             []RawBodyHook             // Functions run on every raw response body before decoding
+	rejectEmptyBody          bool                      // Fail 2xx responses without a body (except 204 and 205)
+	nonNullableFields        []string                  // JSON names of fields of T that may not be null
+	validateSchema           func(body []byte) error   // Validates bodies against the schema set with WithResponseSchema
+	assumedCharset           string                    // Charset of successful bodies that declare none (empty = UTF-8)
+	bufferPool               bool                      // Read bodies into pooled buffers; RawBody is not set
+	afterResponseHooks       []AfterResponseHook       // Functions run after every Execute and ExecuteRaw call
+	fallback                 FallbackFunc[T]           // Provides a response when Execute fails (nil = none)
+	progress                 ProgressFunc              // Reports the progress of reading response bodies (nil = none)
+
+	decode func(ctx context.Context, data []byte, v *T) error // Decodes successful bodies (nil = encoding/json)
+
+	// Streaming configuration
+	sseReconnectDelay time.Duration // Delay before reconnecting a server-sent events stream (0 = no reconnect)
+
+	// Pagination configuration applied in GetPage
+	pageItemsField string // Field of list responses holding the items (empty = DefaultPageItemsField)
+	pageTotalField string // Field of list responses holding the total (empty = DefaultPageTotalField)
+
+	// Debugging
+	debugDump   io.Writer  // Receives dumps of every request and response (nil = disabled)
+	debugDumpMu sync.Mutex // Keeps dumps of concurrent requests from interleaving
+
+	// Cumulative request counters returned by Stats
+	stats clientStats
+
+	// Lifecycle of background tasks
+	idleConnPruneInterval time.Duration // Interval for closing idle connections (0 = disabled)
+	stopPruning           chan struct{}
+	closeOnce             sync.Once
+	closed                atomic.Bool
 }
 
 // GenericClientOption is a function type for configuring the GenericClient.
 type GenericClientOption[T any] func(*GenericClient[T])
 
+// contextValue is a key/value pair attached to the context of every request.
+type contextValue struct {
+	key   any
+	value any
+}
+
 // Response represents the response from an HTTP request with generic type support.
+// BytesRead and BytesWritten support bandwidth accounting: BytesRead is the length of
+// the response body (len(RawBody)) and BytesWritten the length of the request body,
+// or -1 if it was not known before sending, e.g. for a streaming body.
 type Response[T any] struct {
-	Data       T
-	Headers    http.Header
-	RawBody    []byte
-	StatusCode int
+	Data         T
+	Headers      http.Header
+	RawBody      []byte
+	StatusCode   int
+	BytesRead    int
+	BytesWritten int
+}
+
+// BodyReader returns a new io.Reader over RawBody.
+// Each call returns an independent reader positioned at the start of the body,
+// which is convenient for passing the body to another decoder or writer.
+func (r *Response[T]) BodyReader() io.Reader {
+	return bytes.NewReader(r.RawBody)
+}
+
+// ResponseError is the error returned for responses with status code >= 400 when the client
+// decodes error bodies into a custom type configured with WithErrorType.
+// Use errors.As to retrieve the decoded value, or an *ErrorResponse for the same response.
+type ResponseError struct {
+	statusCode int
+	value      any            // Decoded error value (E, or *E when only *E implements error), nil if not decoded
+	response   *ErrorResponse // Default structured error for the same response
+}
+
+// StatusCode returns the HTTP status code of the response.
+func (e *ResponseError) StatusCode() int {
+	return e.statusCode
+}
+
+// Error returns the message of the decoded value when it implements the error interface,
+// or the message of the default ErrorResponse otherwise.
+func (e *ResponseError) Error() string {
+	if err, ok := e.value.(error); ok {
+		return fmt.Sprintf("http %d: %s", e.statusCode, err.Error())
+	}
+
+	return e.response.Error()
+}
+
+// Unwrap returns the decoded value when it implements the error interface, so that
+// errors.Is and errors.As consider it.
+func (e *ResponseError) Unwrap() error {
+	if err, ok := e.value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// As sets target to the decoded value when target points to a variable of the decoded
+// type or a pointer to it, or to the default ErrorResponse when target is an **ErrorResponse.
+func (e *ResponseError) As(target any) bool {
+	if errResp, ok := target.(**ErrorResponse); ok {
+		*errResp = e.response
+
+		return true
+	}
+
+	if e.value == nil {
+		return false
+	}
+
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Pointer || targetValue.IsNil() {
+		return false
+	}
+
+	dest := targetValue.Elem()
+	value := reflect.ValueOf(e.value)
+
+	switch {
+	case value.Type().AssignableTo(dest.Type()):
+		dest.Set(value)
+	case value.Kind() == reflect.Pointer && value.Elem().Type().AssignableTo(dest.Type()):
+		dest.Set(value.Elem())
+	case dest.Type() == reflect.PointerTo(value.Type()):
+		copied := reflect.New(value.Type())
+		copied.Elem().Set(value)
+		dest.Set(copied)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// IsSuccess reports whether the response has a 2xx status code.
+func (r *Response[T]) IsSuccess() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// IsRedirect reports whether the response has a 3xx status code.
+func (r *Response[T]) IsRedirect() bool {
+	return r.StatusCode >= 300 && r.StatusCode < 400
+}
+
+// IsClientError reports whether the response has a 4xx status code.
+func (r *Response[T]) IsClientError() bool {
+	return r.StatusCode >= 400 && r.StatusCode < 500
+}
+
+// IsServerError reports whether the response has a 5xx status code.
+func (r *Response[T]) IsServerError() bool {
+	return r.StatusCode >= 500 && r.StatusCode < 600
 }
 
 // ErrorResponse represents an error response from the API.
@@ -83,70 +320,223 @@ func NewGenericClient[T any](options ...GenericClientOption[T]) *GenericClient[T
 		option(client)
 	}
 
-	// If a custom HTTP client was provided, use it
+	// If a custom HTTP client was provided, use it,
+	// otherwise build one using ClientBuilder with the configured options
 	if client.customClient != nil {
 		client.httpClient = client.customClient
-		return client
+	} else {
+		client.httpClient = client.buildHTTPClient()
+	}
+
+	client.startIdleConnPruning()
+
+	return client
+}
+
+// defaultClientConfig holds the configuration set with SetDefaultClientConfig
+// (nil = library defaults).
+var defaultClientConfig atomic.Pointer[Client]
+
+// SetDefaultClientConfig sets the configuration that every GenericClient starts from when it
+// builds its own HTTP client, so that organization-wide defaults such as timeouts, retries
+// and proxies do not have to be repeated at every call site. Options passed to
+// NewGenericClient still override these defaults; clients created with WithHTTPClient are
+// not affected. The builder's configuration is copied, so later changes to the builder
+// have no effect. It is safe for concurrent use, but it is meant to be called once during
+// program initialization: clients created before the call keep their configuration.
+// Pass nil to restore the library defaults.
+func SetDefaultClientConfig(builder *ClientBuilder) {
+	if builder == nil {
+		defaultClientConfig.Store(nil)
+
+		return
 	}
 
-	// Otherwise, build an HTTP client using ClientBuilder with the configured options
-	builder := NewClientBuilder()
+	defaultClientConfig.Store(builder.client.clone())
+}
+
+// newDefaultClientBuilder returns a ClientBuilder starting from the configuration set with
+// SetDefaultClientConfig, or from the library defaults if none is set.
+func newDefaultClientBuilder() *ClientBuilder {
+	if config := defaultClientConfig.Load(); config != nil {
+		return &ClientBuilder{client: config.clone()}
+	}
+
+	return NewClientBuilder()
+}
+
+// buildHTTPClient builds an HTTP client using ClientBuilder,
+// applying only the configuration options that were explicitly set.
+func (c *GenericClient[T]) buildHTTPClient() *http.Client {
+	builder := newDefaultClientBuilder()
 
 	// Apply configuration if set
-	if client.maxIdleConns != nil {
-		builder.WithMaxIdleConns(*client.maxIdleConns)
+	if c.maxIdleConns != nil {
+		builder.WithMaxIdleConns(*c.maxIdleConns)
 	}
 
-	if client.idleConnTimeout != nil {
-		builder.WithIdleConnTimeout(*client.idleConnTimeout)
+	if c.idleConnTimeout != nil {
+		builder.WithIdleConnTimeout(*c.idleConnTimeout)
 	}
 
-	if client.tlsHandshakeTimeout != nil {
-		builder.WithTLSHandshakeTimeout(*client.tlsHandshakeTimeout)
+	if c.tlsHandshakeTimeout != nil {
+		builder.WithTLSHandshakeTimeout(*c.tlsHandshakeTimeout)
 	}
 
-	if client.expectContinueTimeout != nil {
-		builder.WithExpectContinueTimeout(*client.expectContinueTimeout)
+	if c.expectContinueTimeout != nil {
+		builder.WithExpectContinueTimeout(*c.expectContinueTimeout)
 	}
 
-	if client.maxIdleConnsPerHost != nil {
-		builder.WithMaxIdleConnsPerHost(*client.maxIdleConnsPerHost)
+	if c.maxIdleConnsPerHost != nil {
+		builder.WithMaxIdleConnsPerHost(*c.maxIdleConnsPerHost)
 	}
 
-	if client.timeout != nil {
-		builder.WithTimeout(*client.timeout)
+	if c.timeout != nil {
+		builder.WithTimeout(*c.timeout)
 	}
 
-	if client.maxRetries != nil {
-		builder.WithMaxRetries(*client.maxRetries)
+	if c.maxRetries != nil {
+		builder.WithMaxRetries(*c.maxRetries)
 	}
 
-	if client.retryBaseDelay != nil {
-		builder.WithRetryBaseDelay(*client.retryBaseDelay)
+	if c.maxRetriesForStatus != nil {
+		builder.WithMaxRetriesForStatus(c.maxRetriesForStatus)
 	}
 
-	if client.retryMaxDelay != nil {
-		builder.WithRetryMaxDelay(*client.retryMaxDelay)
+	if c.nonRetryableStatus != nil {
+		builder.WithNonRetryableStatusCodes(c.nonRetryableStatus...)
 	}
 
-	if client.retryStrategy != nil {
-		builder.WithRetryStrategy(*client.retryStrategy)
+	if c.retryBaseDelay != nil {
+		builder.WithRetryBaseDelay(*c.retryBaseDelay)
 	}
 
-	if client.disableKeepAlive != nil {
-		builder.WithDisableKeepAlive(*client.disableKeepAlive)
+	if c.retryMaxDelay != nil {
+		builder.WithRetryMaxDelay(*c.retryMaxDelay)
 	}
 
-	if client.logger != nil {
-		builder.WithLogger(client.logger)
+	if c.retryMultiplier != nil {
+		builder.WithRetryMultiplier(*c.retryMultiplier)
 	}
 
-	if client.proxyURL != nil {
-		builder.WithProxy(*client.proxyURL)
+	if c.retryStrategy != nil {
+		builder.WithRetryStrategy(*c.retryStrategy)
 	}
 
-	client.httpClient = builder.Build()
-	return client
+	if c.retryStrategyFunc != nil {
+		builder.WithRetryStrategyFunc(c.retryStrategyFunc)
+	}
+
+	if c.retryableError != nil {
+		builder.WithRetryableErrorFunc(c.retryableError)
+	}
+
+	if c.maxResponseTime != nil {
+		builder.WithMaxResponseTime(*c.maxResponseTime)
+	}
+
+	if c.absoluteMaxDelay != nil {
+		builder.WithAbsoluteMaxDelay(*c.absoluteMaxDelay)
+	}
+
+	if c.retryAfterMaxWait != nil {
+		builder.WithRetryAfterMaxWait(*c.retryAfterMaxWait)
+	}
+
+	if c.respectRetryAfter != nil {
+		builder.WithRespectRetryAfter(*c.respectRetryAfter)
+	}
+
+	if c.perAttemptTimeout != nil {
+		builder.WithPerAttemptTimeout(*c.perAttemptTimeout)
+	}
+
+	if c.responseHeaderTimeout != nil {
+		builder.WithResponseHeaderTimeout(*c.responseHeaderTimeout)
+	}
+
+	if c.bodyReadTimeout != nil {
+		builder.WithBodyReadTimeout(*c.bodyReadTimeout)
+	}
+
+	// Applied after the individual timeouts, which its non-zero fields override
+	if c.phaseTimeouts != nil {
+		builder.WithPhaseTimeouts(*c.phaseTimeouts)
+	}
+
+	if c.retryOnConnectionReset != nil {
+		builder.WithRetryOnConnectionReset(*c.retryOnConnectionReset)
+	}
+
+	if c.maxRetryBodyBytes != nil {
+		builder.WithMaxRetryBodyBytes(*c.maxRetryBodyBytes)
+	}
+
+	if c.disableKeepAlive != nil {
+		builder.WithDisableKeepAlive(*c.disableKeepAlive)
+	}
+
+	if c.disableCompression != nil {
+		builder.WithDisableCompression(*c.disableCompression)
+	}
+
+	if c.logger != nil {
+		builder.WithLogger(c.logger)
+	}
+
+	if c.proxyURL != nil {
+		builder.WithProxy(*c.proxyURL)
+	}
+
+	if c.proxyConnectHeader != nil {
+		builder.WithProxyConnectHeader(c.proxyConnectHeader)
+	}
+
+	if c.deadlineHeader != nil {
+		builder.WithDeadlinePropagationHeader(*c.deadlineHeader)
+	}
+
+	if c.attemptsHeader != nil {
+		builder.WithAttemptsHeader(*c.attemptsHeader)
+	}
+
+	if c.tlsServerName != nil {
+		builder.WithTLSServerName(*c.tlsServerName)
+	}
+
+	if c.minTLSVersion != nil {
+		builder.WithMinTLSVersion(*c.minTLSVersion)
+	}
+
+	if c.dnsCacheTTL != nil {
+		builder.WithDNSCache(*c.dnsCacheTTL)
+	}
+
+	if c.maxConnLifetime != nil {
+		builder.WithMaxConnLifetime(*c.maxConnLifetime)
+	}
+
+	if c.maxHeaderBytes != nil {
+		builder.WithMaxHeaderBytes(*c.maxHeaderBytes)
+	}
+
+	if c.localAddr != nil {
+		builder.WithLocalAddr(c.localAddr)
+	}
+
+	if c.unixSocket != nil {
+		builder.WithUnixSocket(*c.unixSocket)
+	}
+
+	if c.retryBudgetRatio != nil {
+		builder.WithRetryBudget(*c.retryBudgetRatio, c.retryBudgetMinPerSecond)
+	}
+
+	if c.transport != nil {
+		builder.WithBaseTransport(c.transport)
+	}
+
+	return builder.Build()
 }
 
 // WithHTTPClient configures the generic client to use a custom HTTPClient implementation.
@@ -161,14 +551,41 @@ func WithHTTPClient[T any](httpClient HTTPClient) GenericClientOption[T] {
 	}
 }
 
-// WithTimeout sets the request timeout for the generic client.
-// Uses ClientBuilder validation and defaults if the value is out of range.
+// WithTransport sets the http.RoundTripper used by the HTTP client the generic client builds,
+// e.g. a recording transport in tests. Unlike WithHTTPClient, the client's timeout, retries
+// and logging still apply: the transport replaces the standard http.Transport below the
+// retry layer. Options that configure the standard transport, such as proxies, TLS settings,
+// connection pooling or the DNS cache, have no effect on a custom transport.
+// See ClientBuilder.WithBaseTransport for details.
+// It is ignored when WithHTTPClient is set. If transport is nil, the option is ignored.
+func WithTransport[T any](transport http.RoundTripper) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		if transport != nil {
+			c.transport = transport
+		}
+	}
+}
+
+// WithTimeout sets the request timeout for the generic client, enforced per attempt.
+// See ClientBuilder.WithTimeout for details. Uses ClientBuilder validation and defaults if the value is out of range.
 func WithTimeout[T any](timeout time.Duration) GenericClientOption[T] {
 	return func(c *GenericClient[T]) {
 		c.timeout = &timeout
 	}
 }
 
+// WithPerRequestTimeout bounds each request made with the convenience methods (Get, Post,
+// Put, Delete, Patch and Head), including its retries, by a context timeout of d. Unlike
+// WithTimeout, which applies to the whole HTTP client, it caps individual logical
+// operations; whichever timeout expires first ends the request. Requests passed to
+// Execute and ExecuteRaw keep their own context.
+// Pass 0 to disable the per-request timeout (default behavior).
+func WithPerRequestTimeout[T any](d time.Duration) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.perRequestTimeout = d
+	}
+}
+
 // WithMaxIdleConns sets the maximum number of idle connections.
 // Uses ClientBuilder validation and defaults if the value is out of range.
 func WithMaxIdleConns[T any](maxIdleConns int) GenericClientOption[T] {
@@ -193,6 +610,34 @@ func WithTLSHandshakeTimeout[T any](tlsHandshakeTimeout time.Duration) GenericCl
 	}
 }
 
+// WithResponseHeaderTimeout bounds how long the client waits for the response headers.
+// See ClientBuilder.WithResponseHeaderTimeout for details.
+func WithResponseHeaderTimeout[T any](timeout time.Duration) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.responseHeaderTimeout = &timeout
+	}
+}
+
+// WithBodyReadTimeout bounds how long each read of a response body waits for data, failing
+// stalled bodies with an error wrapping ErrBodyReadTimeout.
+// See ClientBuilder.WithBodyReadTimeout for details.
+func WithBodyReadTimeout[T any](timeout time.Duration) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.bodyReadTimeout = &timeout
+	}
+}
+
+// WithPhaseTimeouts sets the timeouts of the phases of a request in one place. Its non-zero
+// fields take precedence over WithTimeout, WithTLSHandshakeTimeout, WithResponseHeaderTimeout,
+// WithBodyReadTimeout and WithMaxResponseTime, while WithPerAttemptTimeout takes precedence
+// over its Attempt field.
+// See ClientBuilder.WithPhaseTimeouts for details.
+func WithPhaseTimeouts[T any](timeouts PhaseTimeouts) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.phaseTimeouts = &timeouts
+	}
+}
+
 // WithExpectContinueTimeout sets the expect continue timeout.
 // Uses ClientBuilder validation and defaults if the value is out of range.
 func WithExpectContinueTimeout[T any](expectContinueTimeout time.Duration) GenericClientOption[T] {
@@ -208,6 +653,14 @@ func WithDisableKeepAlive[T any](disableKeepAlive bool) GenericClientOption[T] {
 	}
 }
 
+// WithDisableCompression sets whether to disable the transparent gzip compression of the transport.
+// See ClientBuilder.WithDisableCompression for details.
+func WithDisableCompression[T any](disableCompression bool) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.disableCompression = &disableCompression
+	}
+}
+
 // WithMaxIdleConnsPerHost sets the maximum number of idle connections per host.
 // Uses ClientBuilder validation and defaults if the value is out of range.
 func WithMaxIdleConnsPerHost[T any](maxIdleConnsPerHost int) GenericClientOption[T] {
@@ -224,6 +677,24 @@ func WithMaxRetries[T any](maxRetries int) GenericClientOption[T] {
 	}
 }
 
+// WithMaxRetriesForStatus sets per-status-code retry limits that override
+// the global maximum number of retries for matching responses.
+// Unlisted status codes use the global maximum.
+func WithMaxRetriesForStatus[T any](maxRetriesForStatus map[int]int) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.maxRetriesForStatus = copyMaxRetriesForStatus(maxRetriesForStatus)
+	}
+}
+
+// WithNonRetryableStatusCodes sets status codes that are never retried even though they
+// are 5xx or 429, e.g. 501 Not Implemented.
+// See ClientBuilder.WithNonRetryableStatusCodes for details.
+func WithNonRetryableStatusCodes[T any](codes ...int) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.nonRetryableStatus = append([]int{}, codes...)
+	}
+}
+
 // WithRetryBaseDelay sets the base delay for retry strategies.
 // Uses ClientBuilder validation and defaults if the value is out of range.
 func WithRetryBaseDelay[T any](baseDelay time.Duration) GenericClientOption[T] {
@@ -240,6 +711,14 @@ func WithRetryMaxDelay[T any](maxDelay time.Duration) GenericClientOption[T] {
 	}
 }
 
+// WithRetryMultiplier sets the growth factor of exponential and jitter backoff delays.
+// Uses ClientBuilder validation and defaults if the value is out of range.
+func WithRetryMultiplier[T any](multiplier float64) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.retryMultiplier = &multiplier
+	}
+}
+
 // WithRetryStrategy sets the retry strategy type (fixed, jitter, or exponential).
 // Uses ClientBuilder validation and defaults if the value is invalid.
 func WithRetryStrategy[T any](strategy Strategy) GenericClientOption[T] {
@@ -258,6 +737,81 @@ func WithRetryStrategyAsString[T any](strategy string) GenericClientOption[T] {
 	}
 }
 
+// WithRetryStrategyFunc sets a custom retry strategy function, e.g. a Fibonacci backoff.
+// It takes precedence over WithRetryStrategy. See ClientBuilder.WithRetryStrategyFunc for details.
+func WithRetryStrategyFunc[T any](strategy RetryStrategy) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.retryStrategyFunc = strategy
+	}
+}
+
+// WithRetryableErrorFunc sets the function deciding whether a transport error is retried.
+// See ClientBuilder.WithRetryableErrorFunc and DefaultRetryableError for details.
+func WithRetryableErrorFunc[T any](fn func(error) bool) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.retryableError = fn
+	}
+}
+
+// WithRetryOnConnectionReset sets whether connection resets are always retried, even when
+// WithRetryableErrorFunc rejects them (enabled by default).
+// See ClientBuilder.WithRetryOnConnectionReset for details.
+func WithRetryOnConnectionReset[T any](enabled bool) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.retryOnConnectionReset = &enabled
+	}
+}
+
+// WithAbsoluteMaxDelay sets a hard ceiling on every retry delay, whatever the strategy or
+// Retry-After header. See ClientBuilder.WithAbsoluteMaxDelay for details.
+func WithAbsoluteMaxDelay[T any](d time.Duration) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.absoluteMaxDelay = &d
+	}
+}
+
+// WithRespectRetryAfter sets whether the client waits as long as the Retry-After header of
+// 429 and 503 responses asks. See ClientBuilder.WithRespectRetryAfter for details.
+func WithRespectRetryAfter[T any](enabled bool) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.respectRetryAfter = &enabled
+	}
+}
+
+// WithRetryAfterMaxWait sets the longest Retry-After delay the client honors; longer ones
+// fail the request with ErrRetryAfterTooLong. See ClientBuilder.WithRetryAfterMaxWait for details.
+func WithRetryAfterMaxWait[T any](d time.Duration) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.retryAfterMaxWait = &d
+	}
+}
+
+// WithMaxResponseTime bounds the total time of a request, including all retries, and makes
+// slower requests fail with an error wrapping ErrResponseTooSlow.
+// See ClientBuilder.WithMaxResponseTime for details.
+func WithMaxResponseTime[T any](maxResponseTime time.Duration) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.maxResponseTime = &maxResponseTime
+	}
+}
+
+// WithPerAttemptTimeout bounds each attempt of a request with a fresh timeout, so that every
+// retry gets the full timeout. See ClientBuilder.WithPerAttemptTimeout for details.
+func WithPerAttemptTimeout[T any](timeout time.Duration) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.perAttemptTimeout = &timeout
+	}
+}
+
+// WithMaxRetryBodyBytes buffers request bodies without GetBody of at most n bytes so that
+// they can be retried; larger bodies are sent once. See ClientBuilder.WithMaxRetryBodyBytes
+// for details.
+func WithMaxRetryBodyBytes[T any](n int) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.maxRetryBodyBytes = &n
+	}
+}
+
 // WithLogger sets the logger for logging HTTP operations (retries, errors, etc.).
 // Pass nil to disable logging (default behavior).
 func WithLogger[T any](logger *slog.Logger) GenericClientOption[T] {
@@ -275,106 +829,1297 @@ func WithProxy[T any](proxyURL string) GenericClientOption[T] {
 	}
 }
 
-// Execute performs an HTTP request and returns a typed response.
-// It executes the request, reads the response body,
-// and unmarshals the JSON response into the generic type T.
-// Returns an error if the HTTP status code is >= 400.
-func (c *GenericClient[T]) Execute(req *http.Request) (*Response[T], error) {
-	// Log raw request details
-	if c.logger != nil {
-		c.logger.Debug("Executing HTTP request",
-			"method", req.Method,
-			"url", req.URL.String(),
-		)
-		c.logger.Debug("Request headers",
-			"headers", req.Header,
-		)
-
-		// Log request body if present
-		if req.Body != nil {
-			body, err := io.ReadAll(req.Body)
-			req.Body.Close()
-			if err == nil {
-				c.logger.Debug("Request body (raw)",
-					"body", string(body),
-					"length", len(body),
-				)
-				// Restore the body for actual request
-				req.Body = io.NopCloser(strings.NewReader(string(body)))
-			}
-		}
+// WithProxyConnectHeader sets headers sent on the CONNECT request when tunneling HTTPS
+// through an HTTP proxy. See ClientBuilder.WithProxyConnectHeader for details.
+func WithProxyConnectHeader[T any](header http.Header) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.proxyConnectHeader = header.Clone()
 	}
+}
 
-	// Execute the request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("execute http request: %w", err)
+// WithTLSServerName sets the server name sent via SNI and used to verify the server
+// certificate, overriding the host of the request URL.
+// See ClientBuilder.WithTLSServerName for details.
+func WithTLSServerName[T any](serverName string) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.tlsServerName = &serverName
 	}
-	defer resp.Body.Close()
+}
 
-	// Log raw response details
-	if c.logger != nil {
-		c.logger.Debug("Received HTTP response",
-			"status", resp.Status,
-			"status_code", resp.StatusCode,
-			"url", req.URL.String(),
-			"method", req.Method,
-		)
-		c.logger.Debug("Response headers",
-			"headers", resp.Header,
-		)
+// WithMinTLSVersion sets the minimum TLS version accepted by the client, e.g. tls.VersionTLS12.
+// See ClientBuilder.WithMinTLSVersion for details.
+func WithMinTLSVersion[T any](version uint16) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.minTLSVersion = &version
 	}
+}
 
-	// Read the response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response body: %w", err)
+// WithMaxConnLifetime caps the age of pooled connections, so that connections older than
+// lifetime are closed once idle and replaced by new ones.
+// See ClientBuilder.WithMaxConnLifetime for details.
+func WithMaxConnLifetime[T any](lifetime time.Duration) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.maxConnLifetime = &lifetime
 	}
+}
 
-	// Log raw response body
-	if c.logger != nil {
-		c.logger.Debug("Response body (raw)",
-			"body", string(body),
-			"length", len(body),
-			"content_type", resp.Header.Get("Content-Type"),
-		)
+// WithDNSCache caches the addresses resolved for each host for the given TTL.
+// See ClientBuilder.WithDNSCache for details.
+func WithDNSCache[T any](ttl time.Duration) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.dnsCacheTTL = &ttl
 	}
+}
 
-	// Check for HTTP errors
-	if resp.StatusCode >= 400 {
-		return nil, c.handleErrorResponse(resp.StatusCode, body)
+// WithMaxHeaderBytes limits the size of the response headers the client accepts.
+// See ClientBuilder.WithMaxHeaderBytes for details.
+func WithMaxHeaderBytes[T any](maxHeaderBytes int64) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.maxHeaderBytes = &maxHeaderBytes
 	}
+}
 
-	// Parse the response
-	response := &Response[T]{
-		StatusCode: resp.StatusCode,
-		Headers:    resp.Header,
-		RawBody:    body,
+// WithLocalAddr binds outgoing connections to the given local address, e.g. to send
+// requests from a specific source IP. See ClientBuilder.WithLocalAddr for details.
+func WithLocalAddr[T any](addr net.Addr) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.localAddr = addr
 	}
+}
 
-	// Unmarshal JSON response if body is not empty
-	if len(body) > 0 {
-		if err := json.Unmarshal(body, &response.Data); err != nil {
-			return nil, fmt.Errorf("unmarshal response json: %w", err)
+// WithUnixSocket sends all requests over the unix domain socket at the given path; the URL
+// scheme and host become placeholders. See ClientBuilder.WithUnixSocket for details.
+func WithUnixSocket[T any](path string) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.unixSocket = &path
+	}
+}
+
+// WithDeadlinePropagationHeader sets the name of a header used to propagate the request
+// context deadline to the server, e.g. "X-Request-Timeout" or "grpc-timeout".
+// See ClientBuilder.WithDeadlinePropagationHeader for the header format.
+func WithDeadlinePropagationHeader[T any](name string) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.deadlineHeader = &name
+	}
+}
+
+// WithAttemptsHeader sets the name of a response header, e.g. "X-Httpx-Attempts", that is
+// set to the number of attempts made for the request.
+// See ClientBuilder.WithAttemptsHeader for details.
+func WithAttemptsHeader[T any](name string) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.attemptsHeader = &name
+	}
+}
+
+// WithRetryBudget limits the retries of the client across all of its requests.
+// See ClientBuilder.WithRetryBudget for how ratio and minPerSecond are applied.
+func WithRetryBudget[T any](ratio float64, minPerSecond int) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.retryBudgetRatio = &ratio
+		c.retryBudgetMinPerSecond = minPerSecond
+	}
+}
+
+// WithIdleConnPruneInterval periodically closes the idle connections of the underlying
+// HTTP client at the given interval. This keeps long-lived clients from reusing stale
+// connections to backends whose addresses rotate frequently.
+// The pruning runs in a background goroutine that is stopped by Close.
+// Zero or negative values disable pruning (default behavior).
+func WithIdleConnPruneInterval[T any](interval time.Duration) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.idleConnPruneInterval = interval
+	}
+}
+
+// WithContextValue attaches a value to the context of every request executed by the client,
+// making per-client metadata (trace IDs, tenant IDs, etc.) available to transport wrappers
+// and other middleware through req.Context().Value(key).
+// As with context.WithValue, the key should be of a user-defined, comparable type to avoid
+// collisions. A nil key is ignored. Values set by the caller on the request context take
+// precedence over values configured with this option.
+func WithContextValue[T any](key, value any) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		if key != nil {
+			c.contextValues = append(c.contextValues, contextValue{key: key, value: value})
+		}
+	}
+}
+
+// WithBaseQueryParam adds a query parameter to every request executed by the client,
+// e.g. an API version or key required on all calls.
+// Parameters already present in the request URL are never overridden.
+// An empty key is ignored.
+func WithBaseQueryParam[T any](key, value string) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		if key == "" {
+			return
+		}
+
+		if c.baseQueryParams == nil {
+			c.baseQueryParams = make(url.Values)
+		}
+
+		c.baseQueryParams.Add(key, value)
+	}
+}
+
+// WithBaseQueryParams adds multiple query parameters to every request executed by the client.
+// Parameters already present in the request URL are never overridden.
+// Empty keys are ignored.
+func WithBaseQueryParams[T any](params map[string]string) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		for key, value := range params {
+			WithBaseQueryParam[T](key, value)(c)
+		}
+	}
+}
+
+// RequestEditor is a function that modifies a request before it is sent.
+// Returning an error aborts the request.
+type RequestEditor func(req *http.Request) error
+
+// WithRequestEditor adds a function that is run on every request executed by the client,
+// after all other client-level request configuration and just before the request is sent.
+// It is an escape hatch for changes not covered by other options, such as computed
+// signatures. Editors run in the order they were added and receive a copy of the request,
+// so the caller's request is never modified. An error returned by an editor aborts the
+// request. Nil editors are ignored.
+func WithRequestEditor[T any](editor RequestEditor) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		if editor != nil {
+			c.requestEditors = append(c.requestEditors, editor)
+		}
+	}
+}
+
+// RequestValidator is a function that checks a request before it is sent.
+// Returning an error aborts the request.
+type RequestValidator func(req *http.Request) error
+
+// WithRequestValidator adds a function that checks every request executed by the client
+// before it is sent, to enforce policies centrally, e.g. that all requests carry an
+// X-Tenant-ID header or use an allowed method. Validators run in the order they were
+// added, after all client-level request configuration including request editors, so they
+// see the request as it is sent. An error returned by a validator aborts the request
+// before any network call and is returned wrapped. Validators must not modify the
+// request. Nil validators are ignored.
+func WithRequestValidator[T any](validate RequestValidator) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		if validate != nil {
+			c.requestValidators = append(c.requestValidators, validate)
+		}
+	}
+}
+
+// WithPreflight sends a cheap probe request, e.g. OPTIONS or HEAD, before every upload
+// (a POST, PUT or PATCH request with a body) to check that the endpoint is healthy, and
+// skips the upload if the probe fails. This saves bandwidth for expensive uploads when
+// the backend is down. The probe is sent to path on the host of the upload, or to the
+// upload URL itself when path is empty, with the headers of the upload and no body.
+// It fails when it returns a transport error or a 5xx status, after the client's
+// retries; Execute then returns an error wrapping ErrPreflightFailed. Other requests
+// are sent without a probe, so request counts only grow for uploads.
+// Pass an empty method to disable the probe (default behavior).
+func WithPreflight[T any](method, path string) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.preflightMethod = strings.ToUpper(method)
+		c.preflightPath = path
+	}
+}
+
+// WithGzipResponses requests gzip-compressed responses with "Accept-Encoding: gzip" and
+// decompresses gzip responses before they are decoded, to deliberately reduce bandwidth.
+// net/http only decompresses transparently when it sets Accept-Encoding itself; a
+// manually set header disables that and hands back compressed bodies. This option sets
+// the header and decompresses responses with "Content-Encoding: gzip" in Execute and
+// ExecuteRaw, removing the Content-Encoding and Content-Length headers as net/http does.
+// An Accept-Encoding header already set on a request is kept.
+func WithGzipResponses[T any]() GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.gzipResponses = true
+	}
+}
+
+// ResponseHeaderValidator is a function that checks the headers of a successful response.
+// Returning an error fails the request.
+type ResponseHeaderValidator func(header http.Header) error
+
+// WithResponseHeaderValidator adds a function that checks the headers of every successful
+// response before its body is decoded, e.g. to require a signature header on signed-response
+// APIs. Validators run in the order they were added; an error returned by a validator fails
+// the call. Error responses are reported as usual without running the validators.
+// Nil validators are ignored.
+func WithResponseHeaderValidator[T any](validator ResponseHeaderValidator) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		if validator != nil {
+			c.responseHeaderValidators = append(c.responseHeaderValidators, validator)
+		}
+	}
+}
+
+// maxPooledBufferSize is the capacity above which response buffers are not returned to the
+// pool, so that a single large response does not keep its memory alive.
+const maxPooledBufferSize = 1 << 20
+
+// responseBufferPool holds the buffers response bodies are read into with WithBufferPool.
+var responseBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// WithBufferPool makes Execute read response bodies into buffers taken from a pool shared by
+// all clients, instead of allocating a new slice for every response, to reduce GC pressure
+// in high-throughput clients. The buffer is returned to the pool when Execute returns, so
+// Response.RawBody is always nil and BodyReader returns an empty reader; BytesRead is still
+// set. Hooks set with WithRawBodyHook and decoders set with WithDecoder receive a body that
+// is only valid during the call and must not retain it. Use it only when Data is all you need.
+func WithBufferPool[T any]() GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.bufferPool = true
+	}
+}
+
+// RawBodyHook is a function that receives the raw body of a response, as read by Execute,
+// together with its status code and headers. The body must not be modified.
+type RawBodyHook func(statusCode int, header http.Header, body []byte)
+
+// WithRawBodyHook adds a function that is called with the exact bytes of every response body
+// after it is read and before it is decoded, for both successful and error responses, e.g. to
+// compute content hashes or for audit logging. Error bodies are limited by WithMaxErrorBodyBytes.
+// Hooks run in the order they were added. Nil hooks are ignored.
+func WithRawBodyHook[T any](hook RawBodyHook) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		if hook != nil {
+			c.rawBodyHooks = append(c.rawBodyHooks, hook)
+		}
+	}
+}
+
+// WithAllowEmptyBody sets whether a successful response without a body is accepted.
+// When allowed (default), Execute returns such a response with Data set to the zero value of T.
+// When not allowed, a 2xx response with an empty body fails with ErrEmptyBody, which catches
+// bodies stripped unexpectedly, e.g. by a proxy. Responses that never carry a body are always
+// accepted: 204 No Content, 205 Reset Content and responses to HEAD requests.
+// 304 Not Modified responses are not successful responses in this sense and are returned
+// as is, with an empty body and a zero Data; check StatusCode when sending conditional requests.
+func WithAllowEmptyBody[T any](allow bool) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.rejectEmptyBody = !allow
+	}
+}
+
+// AfterResponseHook is a function that is called when a request executed by the generic
+// client completes. statusCode is 0 when no response was received.
+type AfterResponseHook func(req *http.Request, statusCode int, elapsed time.Duration, err error)
+
+// WithAfterResponse adds a function that is called after every Execute and ExecuteRaw call
+// completes, successful or not, with the request, the response status code, the elapsed time
+// and the returned error, like a deferred callback. This is ideal for emitting RED metrics
+// (rate, errors, duration) uniformly across all calls. Error responses report their status
+// code together with the error. For ExecuteRaw, elapsed covers the time until the response
+// headers were received. Hooks run in the order they were added. Nil hooks are ignored.
+func WithAfterResponse[T any](hook AfterResponseHook) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		if hook != nil {
+			c.afterResponseHooks = append(c.afterResponseHooks, hook)
+		}
+	}
+}
+
+// FallbackFunc is a function that is called with the error of a failed Execute call and
+// returns a response to use instead, e.g. the last known good data from a cache. The bool
+// reports whether the fallback applies; when it is false or the response is nil, the error
+// is returned.
+type FallbackFunc[T any] func(err error) (*Response[T], bool)
+
+// WithFallback sets a function that provides a fallback response when an Execute call
+// ultimately fails, after all retries, so the caller gets a static or cached value
+// instead of an error, a resilience pattern known as graceful degradation. The fallback
+// is called with the error, which it can inspect to decide whether it applies, e.g. only
+// for network errors and 5xx responses. Statistics and after response hooks still observe
+// the failure. ExecuteRaw is not affected. Pass nil to disable the fallback (default behavior).
+func WithFallback[T any](fallback FallbackFunc[T]) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.fallback = fallback
+	}
+}
+
+// WithDebugDump writes the full HTTP exchange of every Execute and ExecuteRaw call to w,
+// in wire format as produced by httputil.DumpRequestOut and httputil.DumpResponse, which is
+// more complete than debug logging for troubleshooting one-off issues. Bodies are buffered
+// for the dump and restored, so the request and the decoding still work. ExecuteRaw dumps
+// only the response headers, leaving the body unread for streaming.
+// The dumps include credentials such as Authorization headers; do not enable it in production.
+// Pass nil to disable dumping (default behavior).
+func WithDebugDump[T any](w io.Writer) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.debugDump = w
+	}
+}
+
+// WithMaxErrorBodyBytes limits how many bytes of an error response body (status code >= 400)
+// are read, avoiding large allocations when a server returns a huge error page, e.g. an
+// HTML 502 page. The ErrorResponse is built from the truncated body. Up to 256 KiB of the
+// rest of the body are read and discarded, so that the connection can be reused; after a
+// longer body the connection is closed instead. Defaults to DefaultMaxErrorBodyBytes; pass
+// 0 or a negative value to read error bodies completely.
+func WithMaxErrorBodyBytes[T any](n int) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.maxErrorBodyBytes = &n
+	}
+}
+
+// WithStatusMessages maps status codes to friendly messages used for error responses whose
+// body does not provide a message, e.g. {404: "Resource not found"}, so that user-facing
+// errors do not show a raw HTML error page or a generic status text. A JSON body with a
+// "message" or "error" field keeps its message. Unmapped status codes keep the current
+// behavior: the raw body, or the status text when the body is empty. Calling it again
+// adds to the existing mapping.
+func WithStatusMessages[T any](messages map[int]string) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		if c.statusMessages == nil {
+			c.statusMessages = make(map[int]string, len(messages))
+		}
+
+		for statusCode, message := range messages {
+			c.statusMessages[statusCode] = message
+		}
+	}
+}
+
+// WithErrorType decodes the JSON body of error responses (status code >= 400) into a new
+// value of type E. Execute then returns a *ResponseError, which carries the status code and
+// lets callers retrieve the decoded value with errors.As, using a target of type *E or E:
+//
+//	var apiErr *APIError
+//	if errors.As(err, &apiErr) { ... }
+//
+// E or *E must implement the error interface to be used as an errors.As target.
+// errors.As with an *ErrorResponse target keeps working, so existing error handling is
+// unaffected. Without this option, error responses are returned as *ErrorResponse.
+func WithErrorType[T any, E any]() GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.decodeErrorValue = func(body []byte) any {
+			value := new(E)
+			if len(body) == 0 || json.Unmarshal(body, value) != nil {
+				return nil
+			}
+
+			// Keep values whose type implements error by value, so errors.Is can compare them
+			if err, ok := any(*value).(error); ok {
+				return err
+			}
+
+			return value
+		}
+	}
+}
+
+// ErrorDecoder is a function that builds the error returned for an error response (status
+// code >= 400) from its status code, body and headers. Returning nil falls back to the
+// default error construction.
+type ErrorDecoder func(statusCode int, body []byte, headers http.Header) error
+
+// WithErrorDecoder sets a function that fully controls the errors returned for error
+// responses, e.g. to read errors nested in arrays, map service-specific codes to sentinel
+// errors or attach headers such as a request ID. The body is read up to the limit set with
+// WithMaxErrorBodyBytes. It takes precedence over WithErrorType and WithStatusMessages;
+// when it returns nil, the error is built as without it. Pass nil to build an
+// *ErrorResponse (default behavior).
+func WithErrorDecoder[T any](decode ErrorDecoder) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.errorDecoder = decode
+	}
+}
+
+// WithDecoder sets the function that decodes successful response bodies into T instead of
+// encoding/json, e.g. to decode XML, YAML or to use a faster JSON library. The content type
+// check of WithExpectedContentType still applies before decoding, and error bodies are
+// decoded as set with WithErrorType. Decoding errors are returned wrapped.
+// Pass nil to decode JSON with encoding/json (default behavior).
+func WithDecoder[T any](decode func(data []byte, v *T) error) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		if decode == nil {
+			c.decode = nil
+			return
+		}
+
+		c.decode = func(_ context.Context, data []byte, v *T) error {
+			return decode(data, v)
+		}
+	}
+}
+
+// WithDecoderCtx is like WithDecoder for decoders that need the request context, e.g.
+// because they make calls of their own to resolve references. The context passed is the
+// context of the request, so such calls are cancelled along with it.
+// Pass nil to decode JSON with encoding/json (default behavior).
+func WithDecoderCtx[T any](decode func(ctx context.Context, data []byte, v *T) error) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.decode = decode
+	}
+}
+
+// WithRejectJSONNulls makes Execute fail with an error wrapping ErrJSONNull when a
+// successful JSON response sets a field of T to null although the field cannot hold
+// null, i.e. it is not a pointer, interface, map or slice. encoding/json silently leaves
+// such fields at their zero value, hiding contract violations. Only the top-level
+// fields of a struct type T are checked, matching names like encoding/json does; the
+// option has no effect for other types or with a custom decoder set with WithDecoder.
+// For other rules, validate the body with WithResponseSchema, or the decoded value in a
+// decoder set with WithDecoder or in the caller.
+func WithRejectJSONNulls[T any]() GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.nonNullableFields = nonNullableJSONFields(reflect.TypeOf((*T)(nil)).Elem())
+	}
+}
+
+// WithAssumedCharset sets the charset of successful response bodies whose Content-Type
+// declares none, e.g. "iso-8859-1" for legacy APIs that send Latin-1 without saying so.
+// Such bodies are transcoded to UTF-8 before they are decoded, so they don't fail with a
+// cryptic decoding error; Response.RawBody keeps the bytes as received. Supported charsets
+// are "utf-8", "us-ascii", "iso-8859-1" (or "latin1") and "windows-1252" (or "cp1252");
+// names are case-insensitive and Execute fails for any other charset. Responses that declare
+// a charset are never transcoded. By default, bodies are decoded as UTF-8 as they are.
+func WithAssumedCharset[T any](charset string) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.assumedCharset = strings.ToLower(strings.TrimSpace(charset))
+	}
+}
+
+// WithExpectedContentType sets the media types, e.g. "application/json", that a successful
+// response must have before its body is decoded. When a response with a body has another
+// Content-Type, such as an HTML error page served with status 200, Execute returns an error
+// wrapping ErrUnexpectedContentType ("expected application/json, got text/html") instead
+// of a confusing decoding error. Parameters such as charset are ignored and the comparison
+// is case-insensitive. Calling it again replaces the expected types.
+func WithExpectedContentType[T any](types ...string) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.expectedContentTypes = c.expectedContentTypes[:0]
+		for _, t := range types {
+			if mediaType := strings.ToLower(strings.TrimSpace(t)); mediaType != "" {
+				c.expectedContentTypes = append(c.expectedContentTypes, mediaType)
+			}
+		}
+	}
+}
+
+// Execute performs an HTTP request and returns a typed response.
+// It executes the request, reads the response body,
+// and unmarshals the JSON response into the generic type T.
+// Returns an error if the HTTP status code is >= 400.
+func (c *GenericClient[T]) Execute(req *http.Request) (*Response[T], error) {
+	start := time.Now()
+	countedReq, attempts := withAttemptCounter(req)
+	resp, statusCode, err := c.execute(countedReq)
+
+	c.stats.record(int(attempts.Load()), err != nil)
+
+	if len(c.afterResponseHooks) > 0 {
+		c.runAfterResponseHooks(req, statusCode, start, err)
+	}
+
+	if err != nil && c.fallback != nil {
+		if fallback, ok := c.fallback(err); ok && fallback != nil {
+			return fallback, nil
+		}
+	}
+
+	return resp, err
+}
+
+// execute performs the request for Execute. It also returns the status code of the
+// response, if one was received, so that it is known even when an error is returned.
+func (c *GenericClient[T]) execute(req *http.Request) (*Response[T], int, error) {
+	if c.closed.Load() {
+		return nil, 0, ErrClientClosed
+	}
+
+	req, err := c.prepareRequest(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := c.preflight(req); err != nil {
+		return nil, 0, err
+	}
+
+	// Log raw request details
+	if c.logger != nil {
+		c.logger.Debug("Executing HTTP request",
+			"method", req.Method,
+			"url", req.URL.String(),
+		)
+		c.logger.Debug("Request headers",
+			"headers", req.Header,
+		)
+
+		// Log request body if present
+		if req.Body != nil {
+			body, err := io.ReadAll(req.Body)
+			req.Body.Close()
+			if err == nil {
+				c.logger.Debug("Request body (raw)",
+					"body", string(body),
+					"length", len(body),
+				)
+				// Restore the body for actual request
+				req.Body = io.NopCloser(strings.NewReader(string(body)))
+			}
 		}
 	}
 
-	return response, nil
+	c.dumpRequest(req)
+
+	// Execute the request
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("execute http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if c.gzipResponses {
+		decompressGzipResponse(resp)
+	}
+
+	c.trackProgress(resp)
+	c.dumpResponse(resp, true)
+
+	// Log raw response details
+	if c.logger != nil {
+		c.logger.Debug("Received HTTP response",
+			"status", resp.Status,
+			"status_code", resp.StatusCode,
+			"url", req.URL.String(),
+			"method", req.Method,
+		)
+		c.logger.Debug("Response headers",
+			"headers", resp.Header,
+		)
+	}
+
+	// Read the response body, only up to the configured limit for error responses
+	var bodyReader io.Reader = resp.Body
+	if resp.StatusCode >= 400 {
+		if limit := c.errorBodyLimit(); limit > 0 {
+			bodyReader = io.LimitReader(resp.Body, int64(limit))
+		}
+	}
+
+	var body []byte
+	if c.bufferPool {
+		buf := responseBufferPool.Get().(*bytes.Buffer)
+		buf.Reset()
+		defer func() {
+			if buf.Cap() <= maxPooledBufferSize {
+				responseBufferPool.Put(buf)
+			}
+		}()
+
+		_, err = buf.ReadFrom(bodyReader)
+		body = buf.Bytes()
+	} else {
+		body, err = io.ReadAll(bodyReader)
+	}
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("read response body: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		drainErrorBody(resp.Body)
+	}
+
+	// Log raw response body
+	if c.logger != nil {
+		c.logger.Debug("Response body (raw)",
+			"body", string(body),
+			"length", len(body),
+			"content_type", resp.Header.Get("Content-Type"),
+		)
+	}
+
+	for _, hook := range c.rawBodyHooks {
+		hook(resp.StatusCode, resp.Header, body)
+	}
+
+	// Check for HTTP errors
+	if resp.StatusCode >= 400 {
+		// The error, e.g. one built by an ErrorDecoder, may keep the body after Execute returns
+		if c.bufferPool {
+			body = bytes.Clone(body)
+		}
+
+		return nil, resp.StatusCode, c.handleErrorResponse(resp.StatusCode, body, resp.Header)
+	}
+
+	// Validate response headers before trusting the body
+	for _, validate := range c.responseHeaderValidators {
+		if err := validate(resp.Header); err != nil {
+			return nil, resp.StatusCode, fmt.Errorf("validate response headers: %w", err)
+		}
+	}
+
+	// Parse the response
+	response := &Response[T]{
+		StatusCode:   resp.StatusCode,
+		Headers:      resp.Header,
+		BytesRead:    len(body),
+		BytesWritten: requestBodyLength(req),
+	}
+
+	// A pooled body is reused once Execute returns, so it must not escape
+	if !c.bufferPool {
+		response.RawBody = body
+	}
+
+	if len(body) == 0 && c.rejectEmptyBody && expectsBody(req, resp.StatusCode) {
+		return nil, resp.StatusCode, fmt.Errorf("%w: status %d", ErrEmptyBody, resp.StatusCode)
+	}
+
+	// Unmarshal JSON response if body is not empty; HEAD responses never carry a body to decode
+	if len(body) > 0 && req.Method != http.MethodHead {
+		if err := c.checkContentType(resp.Header.Get("Content-Type")); err != nil {
+			return nil, resp.StatusCode, err
+		}
+
+		data, err := c.transcodeBody(resp.Header.Get("Content-Type"), body)
+		if err != nil {
+			return nil, resp.StatusCode, err
+		}
+
+		if c.validateSchema != nil {
+			if err := c.validateSchema(data); err != nil {
+				return nil, resp.StatusCode, err
+			}
+		}
+
+		if c.decode != nil {
+			if err := c.decode(req.Context(), data, &response.Data); err != nil {
+				return nil, resp.StatusCode, fmt.Errorf("decode response body: %w", err)
+			}
+		} else if err := json.Unmarshal(data, &response.Data); err != nil {
+			return nil, resp.StatusCode, fmt.Errorf("unmarshal response json: %w", err)
+		} else if err := c.checkJSONNulls(data); err != nil {
+			return nil, resp.StatusCode, err
+		}
+	}
+
+	return response, resp.StatusCode, nil
+}
+
+// ExecuteRaw performs an HTTP request and returns the raw response without unmarshaling.
+// This is useful when you need direct access to the http.Response, such as for streaming
+// or when the response is not JSON. Error statuses are returned without an error; they
+// are counted as failures by Stats, and after-response hooks get their status code.
+func (c *GenericClient[T]) ExecuteRaw(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	countedReq, attempts := withAttemptCounter(req)
+	resp, err := c.executeRaw(countedReq)
+
+	c.stats.record(int(attempts.Load()), err != nil || resp.StatusCode >= 400)
+
+	if len(c.afterResponseHooks) > 0 {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		c.runAfterResponseHooks(req, statusCode, start, err)
+	}
+
+	return resp, err
+}
+
+// executeRawWith performs req like ExecuteRaw, passes the response to handle and closes
+// its body. It is shared by GetPage, DownloadVerified and CallRPC, which all behave the
+// same way: the retries, request editors and hooks of the client apply, the timeout set
+// with WithPerRequestTimeout bounds the call, and error statuses are returned like by
+// Execute, e.g. as an *ErrorResponse. Unlike ExecuteRaw, statistics and after-response
+// hooks report the error returned by handle, so error statuses and bodies that cannot be
+// decoded count as failures.
+func (c *GenericClient[T]) executeRawWith(req *http.Request, handle func(*http.Response) error) error {
+	start := time.Now()
+	countedReq, attempts := withAttemptCounter(req)
+	resp, err := c.executeRaw(countedReq)
+
+	statusCode := 0
+	if err == nil {
+		statusCode = resp.StatusCode
+		err = handle(resp)
+		_ = resp.Body.Close()
+	}
+
+	c.stats.record(int(attempts.Load()), err != nil)
+
+	if len(c.afterResponseHooks) > 0 {
+		c.runAfterResponseHooks(req, statusCode, start, err)
+	}
+
+	return err
 }
 
-// ExecuteRaw performs an HTTP request and returns the raw response without unmarshaling.
-// This is useful when you need direct access to the http.Response, such as for streaming
-// or when the response is not JSON.
-func (c *GenericClient[T]) ExecuteRaw(req *http.Request) (*http.Response, error) {
+// executeRaw performs the request for ExecuteRaw.
+func (c *GenericClient[T]) executeRaw(req *http.Request) (*http.Response, error) {
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+
+	req, err := c.prepareRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(req); err != nil {
+		return nil, err
+	}
+
+	c.dumpRequest(req)
+
 	// Execute the request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("http request failed: %w", err)
 	}
 
+	if c.gzipResponses {
+		decompressGzipResponse(resp)
+	}
+
+	c.trackProgress(resp)
+	c.dumpResponse(resp, false)
+
 	return resp, nil
 }
 
+// Stats returns a snapshot of the cumulative request counters of the client: requests
+// executed, retries, successes, failures, and how many requests took each number of
+// attempts. Requests made with Execute, ExecuteRaw and the methods built on them are
+// counted; streams are not. It is safe for concurrent use.
+func (c *GenericClient[T]) Stats() ClientStats {
+	return c.stats.snapshot()
+}
+
+// preflight sends the probe set with WithPreflight if req is an upload and returns an
+// error wrapping ErrPreflightFailed if the probe fails.
+func (c *GenericClient[T]) preflight(req *http.Request) error {
+	if c.preflightMethod == "" || req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return nil
+	}
+
+	probeURL := *req.URL
+	if c.preflightPath != "" {
+		probeURL.Path = "/" + strings.TrimPrefix(c.preflightPath, "/")
+		probeURL.RawPath = ""
+		probeURL.RawQuery = ""
+	}
+
+	// The probe is not counted as an attempt of the upload in Stats
+	ctx := context.WithValue(req.Context(), attemptCounterKey{}, nil)
+
+	probe, err := http.NewRequestWithContext(ctx, c.preflightMethod, probeURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPreflightFailed, err)
+	}
+	probe.Header = req.Header.Clone()
+	probe.Header.Del("Content-Type")
+	probe.Header.Del("Content-Encoding")
+	probe.Host = req.Host
+
+	resp, err := c.httpClient.Do(probe)
+	if err == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode < http.StatusInternalServerError {
+			return nil
+		}
+
+		err = fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+
+	if c.logger != nil {
+		c.logger.Warn("Preflight request failed, skipping upload",
+			"preflight_method", probe.Method,
+			"preflight_url", probe.URL.String(),
+			"error", err,
+			"url", req.URL.String(),
+			"method", req.Method,
+		)
+	}
+
+	return fmt.Errorf("%w: %s %s: %w", ErrPreflightFailed, probe.Method, probe.URL.Path, err)
+}
+
+// Warmup opens n connections to the host of url by sending concurrent HEAD requests,
+// pre-populating the idle connection pool so that the first real requests after startup
+// do not pay for connection setup such as DNS resolution and the TLS handshake. Every
+// request holds its connection until all of them have one, so n distinct connections are
+// opened instead of one being reused. n is capped by the idle connections per host limit
+// (WithMaxIdleConnsPerHost) and MaxConnsPerHost of the transport, since connections
+// beyond them would be closed or wait. The status codes of the responses are ignored.
+// With HTTP/2 a single connection is shared by all requests. Warmup requests are not
+// counted in Stats and do not run request editors or hooks. Returns the errors of the
+// requests that failed joined together, or the context error if ctx is done first.
+func (c *GenericClient[T]) Warmup(ctx context.Context, url string, n int) error {
+	if c.closed.Load() {
+		return ErrClientClosed
+	}
+
+	if limit := c.connectionsPerHostLimit(); limit > 0 && n > limit {
+		if c.logger != nil {
+			c.logger.Debug("Capping warmup connections to the per-host limit", "requested", n, "limit", limit)
+		}
+		n = limit
+	}
+
+	if n <= 0 {
+		return nil
+	}
+
+	// Released once every request has a connection or has failed
+	allConnected := make(chan struct{})
+	var pending sync.WaitGroup
+	pending.Add(n)
+	go func() {
+		pending.Wait()
+		close(allConnected)
+	}()
+
+	errs := make([]error, n)
+	var done sync.WaitGroup
+	for i := range n {
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+
+			var arrive sync.Once
+			defer arrive.Do(pending.Done)
+
+			trace := &httptrace.ClientTrace{
+				GotConn: func(httptrace.GotConnInfo) {
+					arrive.Do(pending.Done)
+					select {
+					case <-allConnected:
+					case <-ctx.Done():
+					}
+				},
+			}
+
+			req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodHead, url, nil)
+			if err != nil {
+				errs[i] = fmt.Errorf("create warmup request: %w", err)
+				return
+			}
+
+			resp, err := c.httpClient.Do(req)
+			if err != nil {
+				errs[i] = fmt.Errorf("warmup request: %w", err)
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}(i)
+	}
+	done.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return errors.Join(errs...)
+}
+
+// connectionsPerHostLimit returns how many connections to a host the HTTP client keeps
+// open for reuse, or 0 if it is unknown, e.g. for custom HTTP clients and transports.
+func (c *GenericClient[T]) connectionsPerHostLimit() int {
+	client, ok := c.httpClient.(*http.Client)
+	if !ok {
+		return 0
+	}
+
+	rt := client.Transport
+	if retrier, ok := rt.(*retryTransport); ok {
+		rt = retrier.Transport
+	}
+
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return 0
+	}
+
+	limit := transport.MaxIdleConnsPerHost
+	if limit <= 0 {
+		limit = http.DefaultMaxIdleConnsPerHost
+	}
+	if transport.MaxConnsPerHost > 0 && transport.MaxConnsPerHost < limit {
+		limit = transport.MaxConnsPerHost
+	}
+
+	return limit
+}
+
+// requestBodyLength returns the length of the body of req, or -1 if it is unknown.
+func requestBodyLength(req *http.Request) int {
+	if req.Body == nil || req.Body == http.NoBody {
+		return 0
+	}
+
+	if req.ContentLength > 0 {
+		return int(req.ContentLength)
+	}
+
+	return -1
+}
+
+// decompressGzipResponse replaces the body of a gzip-encoded response with its
+// decompressed content and removes the headers describing the encoded body.
+func decompressGzipResponse(resp *http.Response) {
+	if !strings.EqualFold(strings.TrimSpace(resp.Header.Get("Content-Encoding")), "gzip") {
+		return
+	}
+
+	resp.Body = &gzipBody{body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// gzipBody decompresses a gzip-encoded response body. The gzip reader is created on the
+// first Read, so empty bodies, e.g. of HEAD requests, read as empty.
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.zr == nil && b.err == nil {
+		b.zr, b.err = gzip.NewReader(b.body)
+	}
+
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	return b.zr.Read(p)
+}
+
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
+
+// dumpRequest writes req, including its body, to the writer set with WithDebugDump.
+// The body is restored by httputil.DumpRequestOut.
+func (c *GenericClient[T]) dumpRequest(req *http.Request) {
+	if c.debugDump == nil {
+		return
+	}
+
+	dump, err := httputil.DumpRequestOut(req, true)
+	if err != nil {
+		dump = []byte(fmt.Sprintf("httpx: dump request: %v", err))
+	}
+
+	c.writeDump(dump)
+}
+
+// dumpResponse writes resp to the writer set with WithDebugDump, with its body if body is
+// true. The body is restored by httputil.DumpResponse.
+func (c *GenericClient[T]) dumpResponse(resp *http.Response, body bool) {
+	if c.debugDump == nil {
+		return
+	}
+
+	dump, err := httputil.DumpResponse(resp, body)
+	if err != nil {
+		dump = []byte(fmt.Sprintf("httpx: dump response: %v", err))
+	}
+
+	c.writeDump(dump)
+}
+
+// writeDump writes a dump followed by a blank line, ignoring write errors.
+func (c *GenericClient[T]) writeDump(dump []byte) {
+	c.debugDumpMu.Lock()
+	defer c.debugDumpMu.Unlock()
+
+	_, _ = c.debugDump.Write(dump)
+	_, _ = io.WriteString(c.debugDump, "\n\n")
+}
+
+// expectsBody reports whether a response with the given status code to req is expected
+// to carry a body: a 2xx response other than 204 No Content and 205 Reset Content to a
+// request other than HEAD.
+func expectsBody(req *http.Request, statusCode int) bool {
+	if req.Method == http.MethodHead {
+		return false
+	}
+
+	switch statusCode {
+	case http.StatusNoContent, http.StatusResetContent:
+		return false
+	}
+
+	return statusCode >= 200 && statusCode < 300
+}
+
+// runAfterResponseHooks calls the hooks set with WithAfterResponse. statusCode is 0 when no
+// response was received.
+func (c *GenericClient[T]) runAfterResponseHooks(req *http.Request, statusCode int, start time.Time, err error) {
+	elapsed := time.Since(start)
+	for _, hook := range c.afterResponseHooks {
+		hook(req, statusCode, elapsed, err)
+	}
+}
+
+// checkJSONNulls returns an error wrapping ErrJSONNull if the JSON object in body sets
+// one of the fields configured with WithRejectJSONNulls to null.
+func (c *GenericClient[T]) checkJSONNulls(body []byte) error {
+	if len(c.nonNullableFields) == 0 {
+		return nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		// Not an object, so there are no fields to check
+		return nil
+	}
+
+	for key, value := range object {
+		if string(value) != "null" {
+			continue
+		}
+
+		for _, field := range c.nonNullableFields {
+			// encoding/json matches object keys to field names case-insensitively
+			if strings.EqualFold(key, field) {
+				return fmt.Errorf("%w: %q", ErrJSONNull, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// nonNullableJSONFields returns the JSON names of the exported fields of the struct type t
+// (or of the struct t points to) that cannot hold null.
+func nonNullableJSONFields(t reflect.Type) []string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+			continue
+		}
+
+		fields = append(fields, name)
+	}
+
+	return fields
+}
+
+// checkContentType returns an error if contentType does not match the media types
+// configured with WithExpectedContentType.
+func (c *GenericClient[T]) checkContentType(contentType string) error {
+	if len(c.expectedContentTypes) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	if slices.Contains(c.expectedContentTypes, mediaType) {
+		return nil
+	}
+
+	if mediaType == "" {
+		mediaType = "no content type"
+	}
+
+	return fmt.Errorf("%w: expected %s, got %s", ErrUnexpectedContentType, strings.Join(c.expectedContentTypes, " or "), mediaType)
+}
+
+// transcodeBody returns body transcoded to UTF-8 from the charset set with
+// WithAssumedCharset if contentType declares no charset. Otherwise body is returned as is.
+func (c *GenericClient[T]) transcodeBody(contentType string, body []byte) ([]byte, error) {
+	if c.assumedCharset == "" {
+		return body, nil
+	}
+
+	if _, params, err := mime.ParseMediaType(contentType); err == nil && params["charset"] != "" {
+		return body, nil
+	}
+
+	switch c.assumedCharset {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return body, nil
+	case "iso-8859-1", "iso8859-1", "latin1", "l1":
+		return transcodeSingleByte(body, nil), nil
+	case "windows-1252", "cp1252":
+		return transcodeSingleByte(body, &windows1252), nil
+	default:
+		return nil, fmt.Errorf("unsupported assumed charset %q", c.assumedCharset)
+	}
+}
+
+// windows1252 maps the bytes 0x80-0x9F of windows-1252 to Unicode. The remaining bytes
+// match ISO-8859-1; the five undefined bytes map to the C1 control of the same value.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// transcodeSingleByte transcodes body from ISO-8859-1 to UTF-8, mapping the bytes
+// 0x80-0x9F with c1 if it is not nil.
+func transcodeSingleByte(body []byte, c1 *[32]rune) []byte {
+	out := make([]byte, 0, len(body))
+	for _, b := range body {
+		switch {
+		case b < utf8.RuneSelf:
+			out = append(out, b)
+		case c1 != nil && b < 0xA0:
+			out = utf8.AppendRune(out, c1[b-0x80])
+		default:
+			out = utf8.AppendRune(out, rune(b))
+		}
+	}
+
+	return out
+}
+
+// errorFromResponse reads the body of an error response, up to the configured limit,
+// and returns the error built from it. The caller closes the body.
+func (c *GenericClient[T]) errorFromResponse(resp *http.Response) error {
+	var bodyReader io.Reader = resp.Body
+	if limit := c.errorBodyLimit(); limit > 0 {
+		bodyReader = io.LimitReader(resp.Body, int64(limit))
+	}
+
+	body, err := io.ReadAll(bodyReader)
+	if err != nil {
+		return fmt.Errorf("read response body: %w", err)
+	}
+	drainErrorBody(resp.Body)
+
+	return c.handleErrorResponse(resp.StatusCode, body, resp.Header)
+}
+
+// drainErrorBody discards up to maxErrorBodyDrainBytes of the rest of an error response
+// body truncated to the error body limit, so that its connection can be reused.
+func drainErrorBody(body io.Reader) {
+	_, _ = io.CopyN(io.Discard, body, maxErrorBodyDrainBytes)
+}
+
+// errorBodyLimit returns the maximum number of bytes read from an error response body,
+// or 0 or less when error bodies are read completely.
+func (c *GenericClient[T]) errorBodyLimit() int {
+	if c.maxErrorBodyBytes == nil {
+		return DefaultMaxErrorBodyBytes
+	}
+
+	return *c.maxErrorBodyBytes
+}
+
+// prepareRequest applies the client-level request configuration to req
+// and returns the request to send. The caller's request is not modified.
+func (c *GenericClient[T]) prepareRequest(req *http.Request) (*http.Request, error) {
+	if len(c.contextValues) > 0 {
+		ctx := req.Context()
+		for _, cv := range c.contextValues {
+			// Values already present on the request context take precedence
+			if ctx.Value(cv.key) == nil {
+				ctx = context.WithValue(ctx, cv.key, cv.value)
+			}
+		}
+
+		req = req.WithContext(ctx)
+	}
+
+	if len(c.baseQueryParams) > 0 && req.URL != nil {
+		query := req.URL.Query()
+		missing := make(url.Values)
+
+		for key, values := range c.baseQueryParams {
+			if !query.Has(key) {
+				missing[key] = values
+			}
+		}
+
+		if len(missing) > 0 {
+			// Append to the existing raw query so it is sent as-is, without re-encoding
+			u := *req.URL
+			if u.RawQuery == "" {
+				u.RawQuery = missing.Encode()
+			} else {
+				u.RawQuery = u.RawQuery + "&" + missing.Encode()
+			}
+
+			req = req.WithContext(req.Context())
+			req.URL = &u
+		}
+	}
+
+	if c.gzipResponses && req.Header.Get("Accept-Encoding") == "" {
+		// Set the header on a copy so the caller's request is not modified
+		header := req.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		header.Set("Accept-Encoding", "gzip")
+
+		req = req.WithContext(req.Context())
+		req.Header = header
+	}
+
+	if len(c.requestEditors) > 0 {
+		// Editors may change anything, so they work on a deep copy of the request
+		req = req.Clone(req.Context())
+		for _, edit := range c.requestEditors {
+			if err := edit(req); err != nil {
+				return nil, fmt.Errorf("edit request: %w", err)
+			}
+		}
+	}
+
+	for _, validate := range c.requestValidators {
+		if err := validate(req); err != nil {
+			return nil, fmt.Errorf("validate request: %w", err)
+		}
+	}
+
+	return req, nil
+}
+
 // Do performs an HTTP request and returns a typed response.
 // This method is designed to work seamlessly with the RequestBuilder.
 // It's an alias for Execute but with a more familiar name for those used to http.Client.Do().
@@ -389,7 +2134,7 @@ func (c *GenericClient[T]) Get(url string) (*Response[T], error) {
 		return nil, fmt.Errorf("create GET request: %w", err)
 	}
 
-	return c.Execute(req)
+	return c.executeWithTimeout(req)
 }
 
 // Post performs a POST request with the specified body and returns a typed response.
@@ -399,7 +2144,7 @@ func (c *GenericClient[T]) Post(url string, body io.Reader) (*Response[T], error
 		return nil, fmt.Errorf("create POST request: %w", err)
 	}
 
-	return c.Execute(req)
+	return c.executeWithTimeout(req)
 }
 
 // Put performs a PUT request with the specified body and returns a typed response.
@@ -409,7 +2154,7 @@ func (c *GenericClient[T]) Put(url string, body io.Reader) (*Response[T], error)
 		return nil, fmt.Errorf("create PUT request: %w", err)
 	}
 
-	return c.Execute(req)
+	return c.executeWithTimeout(req)
 }
 
 // Delete performs a DELETE request and returns a typed response.
@@ -419,7 +2164,7 @@ func (c *GenericClient[T]) Delete(url string) (*Response[T], error) {
 		return nil, fmt.Errorf("create DELETE request: %w", err)
 	}
 
-	return c.Execute(req)
+	return c.executeWithTimeout(req)
 }
 
 // Patch performs a PATCH request with the specified body and returns a typed response.
@@ -429,29 +2174,144 @@ func (c *GenericClient[T]) Patch(url string, body io.Reader) (*Response[T], erro
 		return nil, fmt.Errorf("create PATCH request: %w", err)
 	}
 
-	return c.Execute(req)
+	return c.executeWithTimeout(req)
+}
+
+// Head performs a HEAD request and returns a response with the status code and headers,
+// e.g. to probe Content-Length or Last-Modified. The body is not decoded and Data is
+// the zero value of T.
+func (c *GenericClient[T]) Head(url string) (*Response[T], error) {
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create HEAD request: %w", err)
+	}
+
+	return c.executeWithTimeout(req)
+}
+
+// executeWithTimeout executes a request of a convenience method, bounded by the timeout
+// set with WithPerRequestTimeout. The body is fully read by Execute, so the timeout
+// context is released when it returns.
+func (c *GenericClient[T]) executeWithTimeout(req *http.Request) (*Response[T], error) {
+	if c.perRequestTimeout <= 0 {
+		return c.Execute(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), c.perRequestTimeout)
+	defer cancel()
+
+	return c.Execute(req.WithContext(ctx))
+}
+
+// Close releases the resources held by the client: it stops background tasks,
+// such as idle connection pruning, and closes the idle connections of the
+// underlying HTTP client when the GenericClient built it. A client passed with
+// WithHTTPClient is left untouched, as it may be shared with other code.
+// The client is unusable after Close; subsequent requests fail with ErrClientClosed.
+// It is safe to call Close multiple times.
+func (c *GenericClient[T]) Close() error {
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+
+		if c.stopPruning != nil {
+			close(c.stopPruning)
+		}
+
+		if c.customClient != nil {
+			return
+		}
+
+		if closer, ok := c.httpClient.(idleConnectionsCloser); ok {
+			closer.CloseIdleConnections()
+		}
+	})
+
+	return nil
+}
+
+// idleConnectionsCloser is implemented by HTTP clients and transports that can
+// release their idle connections, such as *http.Client and *http.Transport.
+type idleConnectionsCloser interface {
+	CloseIdleConnections()
+}
+
+// startIdleConnPruning starts the background goroutine that periodically closes
+// idle connections, if pruning is enabled and the HTTP client supports it.
+func (c *GenericClient[T]) startIdleConnPruning() {
+	if c.idleConnPruneInterval <= 0 {
+		return
+	}
+
+	closer, ok := c.httpClient.(idleConnectionsCloser)
+	if !ok {
+		if c.logger != nil {
+			c.logger.Warn("HTTP client does not support closing idle connections, pruning disabled",
+				"client_type", fmt.Sprintf("%T", c.httpClient),
+			)
+		}
+
+		return
+	}
+
+	c.stopPruning = make(chan struct{})
+
+	go func(interval time.Duration, stop <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				closer.CloseIdleConnections()
+			}
+		}
+	}(c.idleConnPruneInterval, c.stopPruning)
 }
 
 // handleErrorResponse handles HTTP error responses.
-// It attempts to unmarshal the error response as JSON, and if that fails,
-// uses the raw body as the error message.
-func (c *GenericClient[T]) handleErrorResponse(statusCode int, body []byte) error {
+// It uses the error decoder if set, and otherwise attempts to unmarshal the error
+// response as JSON, and if that fails, uses the raw body as the error message.
+func (c *GenericClient[T]) handleErrorResponse(statusCode int, body []byte, headers http.Header) error {
+	if c.errorDecoder != nil {
+		if err := c.errorDecoder(statusCode, body, headers); err != nil {
+			return err
+		}
+	}
+
 	errorResp := &ErrorResponse{
 		StatusCode: statusCode,
 	}
 
 	// Try to unmarshal error response
+	provided := false
 	if len(body) > 0 {
 		if err := json.Unmarshal(body, errorResp); err != nil {
 			// If unmarshaling fails, use raw body as message
 			errorResp.Message = string(body)
+		} else {
+			provided = errorResp.Message != "" || errorResp.ErrorMsg != ""
 		}
 	}
 
+	// Prefer a configured message over the raw body or the status text
+	if message, ok := c.statusMessages[statusCode]; ok && !provided {
+		errorResp.Message = message
+	}
+
 	// Set default message if none provided
 	if errorResp.Message == "" && errorResp.ErrorMsg == "" {
 		errorResp.Message = http.StatusText(statusCode)
 	}
 
+	if c.decodeErrorValue != nil {
+		return &ResponseError{
+			statusCode: statusCode,
+			value:      c.decodeErrorValue(body),
+			response:   errorResp,
+		}
+	}
+
 	return errorResp
 }
@@ -2,12 +2,21 @@ package httpx
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -52,8 +61,8 @@ func TestNewGenericClient(t *testing.T) {
 		)
 
 		if httpClient, ok := client.httpClient.(*http.Client); ok {
-			if httpClient.Timeout != timeout {
-				t.Errorf("Expected timeout %v, got %v", timeout, httpClient.Timeout)
+			if got := attemptTimeout(t, httpClient); got != timeout {
+				t.Errorf("Expected timeout %v, got %v", timeout, got)
 			}
 		} else {
 			t.Error("httpClient should be *http.Client")
@@ -318,6 +327,55 @@ func TestGenericClient_ConvenienceMethods(t *testing.T) {
 			t.Errorf("Expected Name 'Patched', got %s", resp.Data.Name)
 		}
 	})
+
+	t.Run("Head method", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodHead {
+				t.Errorf("Expected HEAD, got %s", r.Method)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+			w.Header().Set("Content-Length", "42")
+		}))
+		defer server.Close()
+
+		client := NewGenericClient[User]()
+		resp, err := client.Head(server.URL)
+		if err != nil {
+			t.Fatalf("Head failed: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		if got := resp.Headers.Get("Last-Modified"); got != "Wed, 21 Oct 2015 07:28:00 GMT" {
+			t.Errorf("Expected Last-Modified header, got %q", got)
+		}
+		if got := resp.Headers.Get("Content-Length"); got != "42" {
+			t.Errorf("Expected Content-Length 42, got %q", got)
+		}
+		if resp.Data != (User{}) {
+			t.Errorf("Expected zero Data, got %+v", resp.Data)
+		}
+	})
+
+	t.Run("Head method does not decode a body", func(t *testing.T) {
+		mock := &mockRoundTripper{
+			roundTripFunc: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader("not json")),
+					Request:    req,
+				}, nil
+			},
+		}
+
+		client := NewGenericClient[User](WithHTTPClient[User](&http.Client{Transport: mock}))
+		if _, err := client.Head("http://example.com/users/1"); err != nil {
+			t.Fatalf("Head failed: %v", err)
+		}
+	})
 }
 
 // TestGenericClient_ExecuteRaw tests the ExecuteRaw method
@@ -577,8 +635,8 @@ func TestGenericClient_AllConfigurationOptions(t *testing.T) {
 
 		// Verify the client was built with an HTTP client
 		if httpClient, ok := client.httpClient.(*http.Client); ok {
-			if httpClient.Timeout != timeout {
-				t.Errorf("Expected timeout %v, got %v", timeout, httpClient.Timeout)
+			if got := attemptTimeout(t, httpClient); got != timeout {
+				t.Errorf("Expected timeout %v, got %v", timeout, got)
 			}
 
 			// Verify the transport is configured
@@ -644,8 +702,8 @@ func TestGenericClient_AllConfigurationOptions(t *testing.T) {
 
 		// Should have default timeout from ClientBuilder
 		if httpClient, ok := client.httpClient.(*http.Client); ok {
-			if httpClient.Timeout != DefaultTimeout {
-				t.Errorf("Expected default timeout %v, got %v", DefaultTimeout, httpClient.Timeout)
+			if got := attemptTimeout(t, httpClient); got != DefaultTimeout {
+				t.Errorf("Expected default timeout %v, got %v", DefaultTimeout, got)
 			}
 		}
 	})
@@ -669,8 +727,8 @@ func TestGenericClient_AllConfigurationOptions(t *testing.T) {
 		// ClientBuilder should have applied defaults
 		if httpClient, ok := client.httpClient.(*http.Client); ok {
 			// Should have been corrected to default
-			if httpClient.Timeout != DefaultTimeout {
-				t.Errorf("Expected default timeout %v, got %v", DefaultTimeout, httpClient.Timeout)
+			if got := attemptTimeout(t, httpClient); got != DefaultTimeout {
+				t.Errorf("Expected default timeout %v, got %v", DefaultTimeout, got)
 			}
 		}
 	})
@@ -845,3 +903,1728 @@ func TestGenericClient_WithProxy(t *testing.T) {
 		}
 	})
 }
+
+// idleClosingClient is an HTTPClient that counts CloseIdleConnections calls.
+type idleClosingClient struct {
+	closeIdleCalls atomic.Int32
+}
+
+func (c *idleClosingClient) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func (c *idleClosingClient) CloseIdleConnections() {
+	c.closeIdleCalls.Add(1)
+}
+
+func TestGenericClient_IdleConnPruning(t *testing.T) {
+	t.Run("Prunes periodically until closed", func(t *testing.T) {
+		httpClient := &idleClosingClient{}
+		client := NewGenericClient[User](
+			WithHTTPClient[User](httpClient),
+			WithIdleConnPruneInterval[User](5*time.Millisecond),
+		)
+
+		deadline := time.Now().Add(time.Second)
+		for httpClient.closeIdleCalls.Load() < 2 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		if httpClient.closeIdleCalls.Load() < 2 {
+			t.Fatalf("Expected at least 2 prune calls, got %d", httpClient.closeIdleCalls.Load())
+		}
+
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close() returned error: %v", err)
+		}
+
+		// Allow an in-flight tick to finish, then verify pruning stopped
+		time.Sleep(10 * time.Millisecond)
+		afterClose := httpClient.closeIdleCalls.Load()
+		time.Sleep(30 * time.Millisecond)
+
+		if got := httpClient.closeIdleCalls.Load(); got != afterClose {
+			t.Errorf("Expected pruning to stop after Close, calls went from %d to %d", afterClose, got)
+		}
+
+		// Close must be idempotent
+		if err := client.Close(); err != nil {
+			t.Errorf("Second Close() returned error: %v", err)
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		client := NewGenericClient[User]()
+
+		if client.stopPruning != nil {
+			t.Error("Expected no pruning goroutine by default")
+		}
+
+		if err := client.Close(); err != nil {
+			t.Errorf("Close() returned error: %v", err)
+		}
+	})
+}
+
+func TestGenericClient_Close(t *testing.T) {
+	httpClient := &idleClosingClient{}
+	client := NewGenericClient[User](WithHTTPClient[User](httpClient))
+
+	if _, err := client.Get("http://example.com/users/1"); err != nil {
+		t.Fatalf("Expected request before Close to succeed, got %v", err)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	// A client passed with WithHTTPClient may be shared, so its connections are left open
+	if got := httpClient.closeIdleCalls.Load(); got != 0 {
+		t.Errorf("Expected CloseIdleConnections not to be called, got %d calls", got)
+	}
+
+	if _, err := client.Get("http://example.com/users/1"); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed from Execute after Close, got %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/users/1", nil)
+	if _, err := client.ExecuteRaw(req); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed from ExecuteRaw after Close, got %v", err)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Errorf("Second Close() returned error: %v", err)
+	}
+
+	t.Run("Built client releases its idle connections", func(t *testing.T) {
+		var conns atomic.Int32
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id":1}`))
+		}))
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				conns.Add(1)
+			}
+		}
+		server.Start()
+		defer server.Close()
+
+		client := NewGenericClient[User]()
+		if _, err := client.Get(server.URL); err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close() returned error: %v", err)
+		}
+
+		// The pooled connection was closed, so the underlying client dials again
+		resp, err := client.httpClient.(*http.Client).Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		resp.Body.Close()
+		assertEqual(t, int32(2), conns.Load())
+	})
+}
+
+// capturingClient is an HTTPClient that records the last request it received.
+type capturingClient struct {
+	lastRequest *http.Request
+	statusCode  int
+	body        string
+	header      http.Header
+}
+
+func (c *capturingClient) Do(req *http.Request) (*http.Response, error) {
+	c.lastRequest = req
+
+	statusCode := c.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	header := c.header
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(strings.NewReader(c.body)),
+		Header:     header,
+		Request:    req,
+	}, nil
+}
+
+type testContextKey string
+
+func TestGenericClient_WithContextValue(t *testing.T) {
+	httpClient := &capturingClient{body: `{"id":1}`}
+	client := NewGenericClient[User](
+		WithHTTPClient[User](httpClient),
+		WithContextValue[User](testContextKey("tenant"), "acme"),
+		WithContextValue[User](testContextKey("trace"), "trace-123"),
+		WithContextValue[User](nil, "ignored"),
+	)
+
+	t.Run("Values are attached to the request context", func(t *testing.T) {
+		if _, err := client.Get("http://example.com/users/1"); err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+
+		ctx := httpClient.lastRequest.Context()
+		assertEqual(t, "acme", ctx.Value(testContextKey("tenant")))
+		assertEqual(t, "trace-123", ctx.Value(testContextKey("trace")))
+	})
+
+	t.Run("Request context values take precedence", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), testContextKey("tenant"), "override")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/users/1", nil)
+		if err != nil {
+			t.Fatalf("NewRequest() failed: %v", err)
+		}
+
+		if _, err := client.Execute(req); err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+
+		sentCtx := httpClient.lastRequest.Context()
+		assertEqual(t, "override", sentCtx.Value(testContextKey("tenant")))
+		assertEqual(t, "trace-123", sentCtx.Value(testContextKey("trace")))
+
+		// The caller's request must not be modified
+		if req.Context().Value(testContextKey("trace")) != nil {
+			t.Error("Expected the original request context to be untouched")
+		}
+	})
+
+	t.Run("ExecuteRaw attaches values too", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/users/1", nil)
+		resp, err := client.ExecuteRaw(req)
+		if err != nil {
+			t.Fatalf("ExecuteRaw() failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		assertEqual(t, "acme", httpClient.lastRequest.Context().Value(testContextKey("tenant")))
+	})
+}
+
+func TestGenericClient_WithBaseQueryParams(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		expectedQuery url.Values
+	}{
+		{
+			name:          "URL without query string",
+			url:           "http://example.com/users",
+			expectedQuery: url.Values{"api_version": {"2"}, "key": {"secret"}},
+		},
+		{
+			name:          "URL with existing query string",
+			url:           "http://example.com/users?page=3",
+			expectedQuery: url.Values{"api_version": {"2"}, "key": {"secret"}, "page": {"3"}},
+		},
+		{
+			name:          "Per-request value is not overridden",
+			url:           "http://example.com/users?api_version=1",
+			expectedQuery: url.Values{"api_version": {"1"}, "key": {"secret"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &capturingClient{body: `[]`}
+			client := NewGenericClient[[]User](
+				WithHTTPClient[[]User](httpClient),
+				WithBaseQueryParam[[]User]("api_version", "2"),
+				WithBaseQueryParams[[]User](map[string]string{"key": "secret", "": "ignored"}),
+			)
+
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatalf("NewRequest() failed: %v", err)
+			}
+
+			if _, err := client.Execute(req); err != nil {
+				t.Fatalf("Execute() failed: %v", err)
+			}
+
+			assertEqual(t, tt.expectedQuery, httpClient.lastRequest.URL.Query())
+
+			// The caller's request URL must not be modified
+			if req.URL.String() != tt.url {
+				t.Errorf("Expected original URL %s to be untouched, got %s", tt.url, req.URL.String())
+			}
+		})
+	}
+}
+
+func TestGenericClient_WithRequestEditor(t *testing.T) {
+	var order []string
+
+	httpClient := &capturingClient{body: `{"id":1}`}
+	client := NewGenericClient[User](
+		WithHTTPClient[User](httpClient),
+		WithBaseQueryParam[User]("api_version", "2"),
+		WithRequestEditor[User](func(req *http.Request) error {
+			order = append(order, "first")
+			// Editors see the request after other client-level configuration
+			req.Header.Set("X-Signature", "sig-"+req.URL.RawQuery)
+			return nil
+		}),
+		WithRequestEditor[User](nil),
+		WithRequestEditor[User](func(req *http.Request) error {
+			order = append(order, "second")
+			req.Host = "virtual.example.com"
+			return nil
+		}),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/users/1", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() failed: %v", err)
+	}
+
+	if _, err := client.Execute(req); err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+
+	assertEqual(t, []string{"first", "second"}, order)
+	assertEqual(t, "sig-api_version=2", httpClient.lastRequest.Header.Get("X-Signature"))
+	assertEqual(t, "virtual.example.com", httpClient.lastRequest.Host)
+
+	// The caller's request is left untouched
+	assertEqual(t, "", req.Header.Get("X-Signature"))
+	assertEqual(t, "example.com", req.Host)
+
+	t.Run("Error aborts the request", func(t *testing.T) {
+		editErr := errors.New("signing key unavailable")
+		httpClient := &capturingClient{}
+		client := NewGenericClient[User](
+			WithHTTPClient[User](httpClient),
+			WithRequestEditor[User](func(req *http.Request) error { return editErr }),
+		)
+
+		_, err := client.Get("http://example.com/users/1")
+		if !errors.Is(err, editErr) {
+			t.Fatalf("Expected editor error, got %v", err)
+		}
+
+		if httpClient.lastRequest != nil {
+			t.Error("Expected the request not to be sent")
+		}
+
+		if _, err := client.ExecuteRaw(req); !errors.Is(err, editErr) {
+			t.Errorf("Expected editor error from ExecuteRaw, got %v", err)
+		}
+	})
+}
+
+func TestGenericClient_WithRequestValidator(t *testing.T) {
+	errMissingTenant := errors.New("missing X-Tenant-ID header")
+	requireTenant := func(req *http.Request) error {
+		if req.Header.Get("X-Tenant-ID") == "" {
+			return errMissingTenant
+		}
+		return nil
+	}
+	denyDelete := func(req *http.Request) error {
+		if req.Method == http.MethodDelete {
+			return fmt.Errorf("method %s not allowed", req.Method)
+		}
+		return nil
+	}
+
+	httpClient := &capturingClient{body: `{"id":1}`}
+	client := NewGenericClient[User](
+		WithHTTPClient[User](httpClient),
+		WithRequestValidator[User](requireTenant),
+		WithRequestValidator[User](nil),
+		WithRequestValidator[User](denyDelete),
+	)
+
+	_, err := client.Get("http://example.com/users/1")
+	if !errors.Is(err, errMissingTenant) {
+		t.Fatalf("Expected missing tenant error, got %v", err)
+	}
+	if httpClient.lastRequest != nil {
+		t.Fatal("Expected the request not to be sent")
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, "http://example.com/users/1", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	if _, err := client.ExecuteRaw(req); err == nil || !strings.Contains(err.Error(), "validate request: method DELETE not allowed") {
+		t.Fatalf("Expected disallowed method error, got %v", err)
+	}
+
+	t.Run("Validators see edited requests", func(t *testing.T) {
+		client := NewGenericClient[User](
+			WithHTTPClient[User](httpClient),
+			WithRequestEditor[User](func(req *http.Request) error {
+				req.Header.Set("X-Tenant-ID", "acme")
+				return nil
+			}),
+			WithRequestValidator[User](requireTenant),
+		)
+
+		if _, err := client.Get("http://example.com/users/1"); err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		assertEqual(t, "acme", httpClient.lastRequest.Header.Get("X-Tenant-ID"))
+	})
+}
+
+func TestGenericClient_WithResponseHeaderValidator(t *testing.T) {
+	errMissingSignature := errors.New("missing signature")
+	requireSignature := func(header http.Header) error {
+		if header.Get("X-Signature") == "" {
+			return errMissingSignature
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name        string
+		statusCode  int
+		header      http.Header
+		expectedErr error
+	}{
+		{name: "Valid headers", statusCode: http.StatusOK, header: http.Header{"X-Signature": {"abc"}}},
+		{name: "Missing header", statusCode: http.StatusOK, expectedErr: errMissingSignature},
+		{name: "Error responses skip validation", statusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewGenericClient[User](
+				WithHTTPClient[User](&capturingClient{statusCode: tt.statusCode, body: `{"id":1}`, header: tt.header}),
+				WithResponseHeaderValidator[User](requireSignature),
+				WithResponseHeaderValidator[User](nil),
+			)
+
+			resp, err := client.Get("http://example.com/users/1")
+
+			switch {
+			case tt.expectedErr != nil:
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("Expected %v, got %v", tt.expectedErr, err)
+				}
+			case tt.statusCode >= 400:
+				var errResp *ErrorResponse
+				if !errors.As(err, &errResp) {
+					t.Fatalf("Expected *ErrorResponse, got %v", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Execute() failed: %v", err)
+				}
+				assertEqual(t, 1, resp.Data.ID)
+			}
+		})
+	}
+}
+
+func TestGenericClient_WithRawBodyHook(t *testing.T) {
+	type capture struct {
+		statusCode int
+		header     string
+		body       string
+	}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+	}{
+		{name: "Successful response", statusCode: http.StatusOK, body: `{"id":1}`},
+		{name: "Error response", statusCode: http.StatusBadRequest, body: `{"message":"bad request"}`},
+		{name: "Undecodable body", statusCode: http.StatusOK, body: `not json`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var captured []capture
+			hook := func(statusCode int, header http.Header, body []byte) {
+				captured = append(captured, capture{statusCode, header.Get("X-Request-Id"), string(body)})
+			}
+
+			client := NewGenericClient[User](
+				WithHTTPClient[User](&capturingClient{
+					statusCode: tt.statusCode,
+					body:       tt.body,
+					header:     http.Header{"X-Request-Id": {"req-1"}},
+				}),
+				WithRawBodyHook[User](hook),
+				WithRawBodyHook[User](nil),
+				WithRawBodyHook[User](hook),
+			)
+
+			_, _ = client.Get("http://example.com/users/1")
+
+			want := capture{tt.statusCode, "req-1", tt.body}
+			if len(captured) != 2 || captured[0] != want || captured[1] != want {
+				t.Errorf("Hook calls = %+v, want 2 calls with %+v", captured, want)
+			}
+		})
+	}
+}
+
+func TestGenericClient_WithAllowEmptyBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		allow       bool
+		method      string
+		statusCode  int
+		body        string
+		expectedErr bool
+	}{
+		{name: "Empty 200 allowed", allow: true, method: http.MethodGet, statusCode: http.StatusOK},
+		{name: "Empty 200 rejected", method: http.MethodGet, statusCode: http.StatusOK, expectedErr: true},
+		{name: "Empty 201 rejected", method: http.MethodPost, statusCode: http.StatusCreated, expectedErr: true},
+		{name: "Non-empty 200 accepted", method: http.MethodGet, statusCode: http.StatusOK, body: `{"id":1}`},
+		{name: "204 accepted", method: http.MethodDelete, statusCode: http.StatusNoContent},
+		{name: "205 accepted", method: http.MethodPost, statusCode: http.StatusResetContent},
+		{name: "304 accepted", method: http.MethodGet, statusCode: http.StatusNotModified},
+		{name: "HEAD accepted", method: http.MethodHead, statusCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewGenericClient[User](
+				WithHTTPClient[User](&capturingClient{statusCode: tt.statusCode, body: tt.body}),
+				WithAllowEmptyBody[User](tt.allow),
+			)
+
+			req, _ := http.NewRequest(tt.method, "http://example.com/users/1", nil)
+			resp, err := client.Execute(req)
+
+			if tt.expectedErr {
+				if !errors.Is(err, ErrEmptyBody) {
+					t.Fatalf("Expected ErrEmptyBody, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Execute() failed: %v", err)
+			}
+			assertEqual(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestGenericClient_WithAfterResponse(t *testing.T) {
+	type call struct {
+		path       string
+		statusCode int
+		failed     bool
+	}
+
+	var calls []call
+	hook := func(req *http.Request, statusCode int, elapsed time.Duration, err error) {
+		if elapsed < 0 {
+			t.Errorf("Expected non-negative elapsed time, got %v", elapsed)
+		}
+		calls = append(calls, call{req.URL.Path, statusCode, err != nil})
+	}
+
+	errTransport := errors.New("connection refused")
+	transport := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Path {
+			case "/missing":
+				return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{"message":"not found"}`)), Header: make(http.Header)}, nil
+			case "/down":
+				return nil, errTransport
+			default:
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"id":1}`)), Header: make(http.Header)}, nil
+			}
+		},
+	}
+
+	client := NewGenericClient[User](
+		WithHTTPClient[User](&http.Client{Transport: transport}),
+		WithAfterResponse[User](hook),
+		WithAfterResponse[User](nil),
+	)
+
+	_, _ = client.Get("http://example.com/users/1")
+	_, _ = client.Get("http://example.com/missing")
+	_, _ = client.Get("http://example.com/down")
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/raw", nil)
+	if resp, err := client.ExecuteRaw(req); err == nil {
+		resp.Body.Close()
+	}
+
+	_ = client.Close()
+	_, _ = client.Get("http://example.com/closed")
+
+	expected := []call{
+		{"/users/1", http.StatusOK, false},
+		{"/missing", http.StatusNotFound, true},
+		{"/down", 0, true},
+		{"/raw", http.StatusOK, false},
+		{"/closed", 0, true},
+	}
+	assertEqual(t, expected, calls)
+
+	// The status code is reported even when the error is not an *ErrorResponse
+	calls = nil
+	decoded := NewGenericClient[User](
+		WithHTTPClient[User](&http.Client{Transport: transport}),
+		WithErrorDecoder[User](func(statusCode int, _ []byte, _ http.Header) error {
+			return fmt.Errorf("api error %d", statusCode)
+		}),
+		WithAfterResponse[User](hook),
+	)
+
+	_, _ = decoded.Get("http://example.com/missing")
+	assertEqual(t, []call{{"/missing", http.StatusNotFound, true}}, calls)
+}
+
+func TestGenericClient_WithFallback(t *testing.T) {
+	errTransport := errors.New("connection refused")
+	transport := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Path {
+			case "/missing":
+				return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{"message":"not found"}`)), Header: make(http.Header)}, nil
+			case "/down":
+				return nil, errTransport
+			default:
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"id":1,"name":"fresh"}`)), Header: make(http.Header)}, nil
+			}
+		},
+	}
+
+	cached := &Response[User]{StatusCode: http.StatusOK, Data: User{ID: 1, Name: "cached"}}
+	var hookErrs int
+
+	client := NewGenericClient[User](
+		WithHTTPClient[User](&http.Client{Transport: transport}),
+		WithAfterResponse[User](func(req *http.Request, statusCode int, elapsed time.Duration, err error) {
+			if err != nil {
+				hookErrs++
+			}
+		}),
+		WithFallback[User](func(err error) (*Response[User], bool) {
+			// Only degrade gracefully on network errors, not on 4xx responses
+			return cached, errors.Is(err, errTransport)
+		}),
+	)
+
+	resp, err := client.Get("http://example.com/users/1")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	assertEqual(t, "fresh", resp.Data.Name)
+
+	resp, err = client.Get("http://example.com/down")
+	if err != nil {
+		t.Fatalf("Expected the fallback response, got error: %v", err)
+	}
+	assertTrue(t, resp == cached)
+
+	_, err = client.Get("http://example.com/missing")
+	var apiErr *ErrorResponse
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a 404 ErrorResponse when the fallback does not apply, got %v", err)
+	}
+
+	assertEqual(t, 2, hookErrs)
+	assertEqual(t, uint64(2), client.Stats().Failures)
+
+	// A fallback without a response does not hide the error
+	client = NewGenericClient[User](
+		WithHTTPClient[User](&http.Client{Transport: transport}),
+		WithFallback[User](func(error) (*Response[User], bool) {
+			return nil, true
+		}),
+	)
+
+	resp, err = client.Get("http://example.com/down")
+	if !errors.Is(err, errTransport) {
+		t.Errorf("Expected the original error for a nil fallback response, got %v", err)
+	}
+	assertTrue(t, resp == nil)
+}
+
+func TestGenericClient_Warmup(t *testing.T) {
+	var newConns atomic.Int32
+	var heads atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			heads.Add(1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	t.Run("Opens distinct connections", func(t *testing.T) {
+		newConns.Store(0)
+		client := NewGenericClient[User]()
+		defer client.Close()
+
+		if err := client.Warmup(context.Background(), server.URL, 4); err != nil {
+			t.Fatalf("Warmup() failed: %v", err)
+		}
+		assertEqual(t, int32(4), newConns.Load())
+
+		if _, err := client.Get(server.URL); err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		assertEqual(t, int32(4), newConns.Load())
+		assertEqual(t, uint64(1), client.Stats().Requests)
+	})
+
+	t.Run("Capped by idle connections per host", func(t *testing.T) {
+		newConns.Store(0)
+		heads.Store(0)
+		client := NewGenericClient[User](WithMaxIdleConnsPerHost[User](2))
+		defer client.Close()
+
+		if err := client.Warmup(context.Background(), server.URL, 10); err != nil {
+			t.Fatalf("Warmup() failed: %v", err)
+		}
+		assertEqual(t, int32(2), newConns.Load())
+		assertEqual(t, int32(2), heads.Load())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		client := NewGenericClient[User](WithRetryStrategyFunc[User](func(int) time.Duration { return time.Millisecond }))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := client.Warmup(ctx, server.URL, 2); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+
+		if err := client.Warmup(context.Background(), "http://127.0.0.1:0", 2); err == nil {
+			t.Error("Expected an error for an unreachable host")
+		}
+
+		_ = client.Close()
+		if err := client.Warmup(context.Background(), server.URL, 2); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("Expected ErrClientClosed, got %v", err)
+		}
+	})
+}
+
+func TestGenericClient_WithDebugDump(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPost && string(body) != `{"name":"Jane"}` {
+			t.Errorf("Server received body %q, want the original body", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Trace", "trace-1")
+		_, _ = w.Write([]byte(`{"id":7,"name":"Jane"}`))
+	}))
+	defer server.Close()
+
+	var dump bytes.Buffer
+	client := NewGenericClient[User](WithDebugDump[User](&dump))
+
+	resp, err := client.Post(server.URL+"/users", strings.NewReader(`{"name":"Jane"}`))
+	if err != nil {
+		t.Fatalf("Post() failed: %v", err)
+	}
+	assertEqual(t, 7, resp.Data.ID)
+
+	for _, want := range []string{
+		"POST /users HTTP/1.1",
+		`{"name":"Jane"}`,
+		"HTTP/1.1 200 OK",
+		"X-Trace: trace-1",
+		`{"id":7,"name":"Jane"}`,
+	} {
+		if !strings.Contains(dump.String(), want) {
+			t.Errorf("Dump does not contain %q:\n%s", want, dump.String())
+		}
+	}
+
+	t.Run("ExecuteRaw leaves the body unread", func(t *testing.T) {
+		dump.Reset()
+
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/raw", nil)
+		raw, err := client.ExecuteRaw(req)
+		if err != nil {
+			t.Fatalf("ExecuteRaw() failed: %v", err)
+		}
+		defer raw.Body.Close()
+
+		body, _ := io.ReadAll(raw.Body)
+		assertEqual(t, `{"id":7,"name":"Jane"}`, string(body))
+
+		if !strings.Contains(dump.String(), "GET /raw HTTP/1.1") || !strings.Contains(dump.String(), "X-Trace: trace-1") {
+			t.Errorf("Expected request and response headers in dump, got:\n%s", dump.String())
+		}
+		if strings.Contains(dump.String(), `"id":7`) {
+			t.Errorf("Expected the response body not to be dumped, got:\n%s", dump.String())
+		}
+	})
+}
+
+func TestGenericClient_WithPerRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-time.After(500 * time.Millisecond):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[User](
+		WithPerRequestTimeout[User](50*time.Millisecond),
+		WithTimeout[User](5*time.Second),
+		WithMaxRetries[User](0),
+	)
+
+	if resp, err := client.Get(server.URL + "/fast"); err != nil || resp.Data.ID != 1 {
+		t.Fatalf("Get() = %v, %v; want ID 1", resp, err)
+	}
+
+	start := time.Now()
+	_, err := client.Get(server.URL + "/slow")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("Expected the per-request timeout to end the request early, took %v", elapsed)
+	}
+
+	t.Run("Execute keeps the request context", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/slow", nil)
+
+		if _, err := client.Execute(req); err != nil {
+			t.Errorf("Expected Execute to ignore the per-request timeout, got %v", err)
+		}
+	})
+}
+
+func TestGenericClient_WithDecoder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(`<User><ID>1</ID><Name>Ada</Name></User>`))
+	}))
+	defer server.Close()
+
+	t.Run("Decoder", func(t *testing.T) {
+		client := NewGenericClient[User](WithDecoder[User](func(data []byte, v *User) error {
+			return xml.Unmarshal(data, v)
+		}))
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		assertEqual(t, User{ID: 1, Name: "Ada"}, resp.Data)
+	})
+
+	t.Run("Decoder with context", func(t *testing.T) {
+		type ctxKey struct{}
+
+		var gotValue any
+		client := NewGenericClient[User](WithDecoderCtx[User](func(ctx context.Context, data []byte, v *User) error {
+			gotValue = ctx.Value(ctxKey{})
+			return xml.Unmarshal(data, v)
+		}))
+
+		ctx := context.WithValue(context.Background(), ctxKey{}, "resolver")
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+		resp, err := client.Execute(req)
+		if err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+		assertEqual(t, "Ada", resp.Data.Name)
+		assertEqual(t, "resolver", gotValue)
+	})
+
+	t.Run("Decoder errors are wrapped", func(t *testing.T) {
+		errDecode := errors.New("unsupported document")
+		client := NewGenericClient[User](WithDecoder[User](func([]byte, *User) error { return errDecode }))
+
+		if _, err := client.Get(server.URL); !errors.Is(err, errDecode) {
+			t.Errorf("Expected the decoder error, got %v", err)
+		}
+	})
+
+	t.Run("Nil restores JSON decoding", func(t *testing.T) {
+		client := NewGenericClient[User](
+			WithDecoder[User](func([]byte, *User) error { return nil }),
+			WithDecoder[User](nil),
+		)
+
+		if _, err := client.Get(server.URL); err == nil || !strings.Contains(err.Error(), "unmarshal response json") {
+			t.Errorf("Expected a JSON decoding error, got %v", err)
+		}
+	})
+}
+
+func TestGenericClient_WithPreflight(t *testing.T) {
+	var healthy atomic.Bool
+	var mu sync.Mutex
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		if r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if !healthy.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	recorded := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		got := requests
+		requests = nil
+		return got
+	}
+
+	client := NewGenericClient[User](
+		WithPreflight[User]("head", "/health"),
+		WithMaxRetries[User](1),
+		WithRetryStrategyFunc[User](func(int) time.Duration { return time.Millisecond }),
+	)
+
+	upload := func(method, path string, body io.Reader) error {
+		req, _ := http.NewRequest(method, server.URL+path, body)
+		req.Header.Set("Authorization", "Bearer token")
+		_, err := client.Execute(req)
+		return err
+	}
+
+	healthy.Store(true)
+	if err := upload(http.MethodPost, "/uploads", strings.NewReader(`{"name":"big"}`)); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	assertEqual(t, []string{"HEAD /health", "POST /uploads"}, recorded())
+
+	healthy.Store(false)
+	if err := upload(http.MethodPut, "/uploads/1", strings.NewReader(`{"name":"big"}`)); !errors.Is(err, ErrPreflightFailed) {
+		t.Fatalf("Expected ErrPreflightFailed, got %v", err)
+	}
+	assertEqual(t, []string{"HEAD /health", "HEAD /health"}, recorded()) // The probe is retried
+
+	// Requests without a body are not probed
+	if err := upload(http.MethodGet, "/uploads/1", nil); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := upload(http.MethodPost, "/uploads", nil); err != nil {
+		t.Fatalf("Post without body failed: %v", err)
+	}
+	assertEqual(t, []string{"GET /uploads/1", "POST /uploads"}, recorded())
+
+	t.Run("Probe the upload URL", func(t *testing.T) {
+		healthy.Store(true)
+		client := NewGenericClient[User](WithPreflight[User](http.MethodOptions, ""))
+
+		req, _ := http.NewRequest(http.MethodPatch, server.URL+"/uploads/1", strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer token")
+		if _, err := client.Execute(req); err != nil {
+			t.Fatalf("Upload failed: %v", err)
+		}
+		assertEqual(t, []string{"OPTIONS /uploads/1", "PATCH /uploads/1"}, recorded())
+		assertEqual(t, map[int]uint64{1: 1}, client.Stats().AttemptCounts)
+	})
+}
+
+func TestGenericClient_WithGzipResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Accept-Encoding") != "gzip" || r.Method == http.MethodHead {
+			_, _ = w.Write([]byte(`{"id":1,"name":"plain"}`))
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(`{"id":1,"name":"compressed"}`))
+		_ = gz.Close()
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[User](WithGzipResponses[User]())
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	assertEqual(t, "compressed", resp.Data.Name)
+	assertEqual(t, "", resp.Headers.Get("Content-Encoding"))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	raw, err := client.ExecuteRaw(req)
+	if err != nil {
+		t.Fatalf("ExecuteRaw() failed: %v", err)
+	}
+	body, _ := io.ReadAll(raw.Body)
+	raw.Body.Close()
+	assertEqual(t, `{"id":1,"name":"compressed"}`, string(body))
+	assertTrue(t, raw.Uncompressed)
+	if req.Header.Get("Accept-Encoding") != "" {
+		t.Error("Expected the caller's request to be left unchanged")
+	}
+
+	t.Run("An explicit Accept-Encoding is kept", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.Header.Set("Accept-Encoding", "identity")
+
+		resp, err := client.Execute(req)
+		if err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+		assertEqual(t, "plain", resp.Data.Name)
+	})
+
+	t.Run("Without the option a manual header returns compressed bytes", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		if _, err := NewGenericClient[User]().Execute(req); err == nil {
+			t.Error("Expected a decoding error for the compressed body")
+		}
+	})
+}
+
+func TestGenericClient_ResponseByteCounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"Ada"}`))
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[User]()
+
+	tests := []struct {
+		name         string
+		body         io.Reader
+		bytesWritten int
+	}{
+		{name: "No body", body: nil, bytesWritten: 0},
+		{name: "Known length", body: strings.NewReader(`{"name":"Ada"}`), bytesWritten: 14},
+		{name: "Streaming body", body: io.MultiReader(strings.NewReader(`{"name":"Ada"}`)), bytesWritten: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Post(server.URL, tt.body)
+			if err != nil {
+				t.Fatalf("Post() failed: %v", err)
+			}
+
+			assertEqual(t, len(`{"id":1,"name":"Ada"}`), resp.BytesRead)
+			assertEqual(t, len(resp.RawBody), resp.BytesRead)
+			assertEqual(t, tt.bytesWritten, resp.BytesWritten)
+		})
+	}
+}
+
+func TestGenericClient_WithRejectJSONNulls(t *testing.T) {
+	type Account struct {
+		ID       int               `json:"id"`
+		Name     string            `json:"name"`
+		Nickname *string           `json:"nickname"`
+		Tags     []string          `json:"tags"`
+		Meta     map[string]string `json:"meta"`
+		Internal string            `json:"-"`
+		Balance  float64
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "No nulls", body: `{"id":1,"name":"Ada"}`},
+		{name: "Nullable fields may be null", body: `{"id":1,"name":"Ada","nickname":null,"tags":null,"meta":null}`},
+		{name: "Unknown and ignored fields may be null", body: `{"id":1,"extra":null,"-":null}`},
+		{name: "Null for a string", body: `{"id":1,"name":null}`, wantErr: `"name"`},
+		{name: "Keys match case-insensitively", body: `{"ID":null}`, wantErr: `"ID"`},
+		{name: "Untagged fields use the field name", body: `{"balance":null}`, wantErr: `"balance"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewGenericClient[Account](WithRejectJSONNulls[Account]()).Get(server.URL)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrJSONNull) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected ErrJSONNull for %s, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("Nulls are accepted by default", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id":1,"name":null}`))
+		}))
+		defer server.Close()
+
+		if _, err := NewGenericClient[Account]().Get(server.URL); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestGenericClient_WithAssumedCharset(t *testing.T) {
+	type Greeting struct {
+		Text string `json:"text"`
+	}
+
+	tests := []struct {
+		name        string
+		charset     string
+		contentType string
+		body        []byte
+		want        string
+		wantErr     string
+	}{
+		{name: "Latin-1 without declared charset", charset: "ISO-8859-1", contentType: "application/json", body: []byte("{\"text\":\"caf\xe9\"}"), want: "café"},
+		{name: "Windows-1252 maps C1 bytes", charset: "cp1252", contentType: "application/json", body: []byte("{\"text\":\"\x80 \x93ok\x94\"}"), want: "€ “ok”"},
+		{name: "Declared charset is not transcoded", charset: "latin1", contentType: "application/json; charset=utf-8", body: []byte(`{"text":"café"}`), want: "café"},
+		{name: "UTF-8 is the default", contentType: "application/json", body: []byte(`{"text":"café"}`), want: "café"},
+		{name: "Unsupported charset", charset: "shift_jis", contentType: "application/json", body: []byte(`{"text":"a"}`), wantErr: `unsupported assumed charset "shift_jis"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = w.Write(tt.body)
+			}))
+			defer server.Close()
+
+			resp, err := NewGenericClient[Greeting](WithAssumedCharset[Greeting](tt.charset)).Get(server.URL)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if resp.Data.Text != tt.want {
+				t.Errorf("Expected text %q, got %q", tt.want, resp.Data.Text)
+			}
+			if !bytes.Equal(resp.RawBody, tt.body) {
+				t.Errorf("Expected RawBody to keep the received bytes, got %q", resp.RawBody)
+			}
+		})
+	}
+}
+
+func TestGenericClient_WithBufferPool(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"user not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":` + strings.TrimPrefix(r.URL.Path, "/users/") + `,"name":"User ` + strings.TrimPrefix(r.URL.Path, "/users/") + `"}`))
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[User](WithBufferPool[User]())
+
+	// Decoded values must not alias pooled buffers reused by later responses
+	var users []User
+	for _, id := range []string{"1", "22", "333"} {
+		resp, err := client.Get(server.URL + "/users/" + id)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if resp.RawBody != nil {
+			t.Errorf("Expected RawBody to be nil with a buffer pool, got %q", resp.RawBody)
+		}
+		if resp.BytesRead == 0 {
+			t.Error("Expected BytesRead to be set")
+		}
+		users = append(users, resp.Data)
+	}
+
+	for i, want := range []string{"User 1", "User 22", "User 333"} {
+		assertEqual(t, want, users[i].Name)
+	}
+
+	_, err := client.Get(server.URL + "/missing")
+	var apiErr *ErrorResponse
+	if !errors.As(err, &apiErr) || apiErr.Message != "user not found" {
+		t.Errorf("Expected the error body to be decoded, got %v", err)
+	}
+}
+
+type bodyError struct {
+	body []byte
+}
+
+func (e *bodyError) Error() string {
+	return string(e.body)
+}
+
+func TestGenericClient_WithBufferPool_ErrorDecoder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(strings.TrimPrefix(r.URL.Path, "/")))
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[User](
+		WithBufferPool[User](),
+		WithErrorDecoder[User](func(_ int, body []byte, _ http.Header) error {
+			return &bodyError{body: body}
+		}),
+	)
+
+	_, first := client.Get(server.URL + "/first-error-body")
+	_, second := client.Get(server.URL + "/ZZZZZZZZZZZZZZ")
+
+	// The body kept by the first error must not alias the pooled buffer reused by the second
+	assertEqual(t, "first-error-body", first.Error())
+	assertEqual(t, "ZZZZZZZZZZZZZZ", second.Error())
+}
+
+func TestGenericClient_WithStatusMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/html":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("<html><body>Not Found</body></html>"))
+		case "/json":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"user 42 does not exist"}`))
+		case "/empty":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("bad input"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[User](
+		WithStatusMessages[User](map[int]string{http.StatusNotFound: "Resource not found"}),
+		WithStatusMessages[User](map[int]string{http.StatusForbidden: "You do not have access"}),
+	)
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/html", expected: "Resource not found"},
+		{path: "/json", expected: "user 42 does not exist"},
+		{path: "/empty", expected: "You do not have access"},
+		{path: "/unmapped", expected: "bad input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, err := client.Get(server.URL + tt.path)
+
+			var apiErr *ErrorResponse
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected *ErrorResponse, got %v", err)
+			}
+			assertEqual(t, tt.expected, apiErr.Message)
+		})
+	}
+}
+
+func TestGenericClient_WithMaxErrorBodyBytes(t *testing.T) {
+	errorPage := strings.Repeat("x", DefaultMaxErrorBodyBytes+100)
+
+	tests := []struct {
+		name            string
+		options         []GenericClientOption[User]
+		statusCode      int
+		expectedMessage string
+	}{
+		{name: "Default limit", statusCode: http.StatusBadGateway, expectedMessage: errorPage[:DefaultMaxErrorBodyBytes]},
+		{name: "Custom limit", options: []GenericClientOption[User]{WithMaxErrorBodyBytes[User](10)}, statusCode: http.StatusBadGateway, expectedMessage: "xxxxxxxxxx"},
+		{name: "No limit", options: []GenericClientOption[User]{WithMaxErrorBodyBytes[User](0)}, statusCode: http.StatusBadGateway, expectedMessage: errorPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]GenericClientOption[User]{
+				WithHTTPClient[User](&capturingClient{statusCode: tt.statusCode, body: errorPage}),
+			}, tt.options...)
+			client := NewGenericClient[User](options...)
+
+			_, err := client.Get("http://example.com/users/1")
+
+			var errResp *ErrorResponse
+			if !errors.As(err, &errResp) {
+				t.Fatalf("Expected *ErrorResponse, got %v", err)
+			}
+
+			assertEqual(t, len(tt.expectedMessage), len(errResp.Message))
+		})
+	}
+
+	t.Run("Successful responses are not limited", func(t *testing.T) {
+		body := `{"id":1,"name":"` + strings.Repeat("n", 100) + `"}`
+		client := NewGenericClient[User](
+			WithHTTPClient[User](&capturingClient{body: body}),
+			WithMaxErrorBodyBytes[User](10),
+		)
+
+		resp, err := client.Get("http://example.com/users/1")
+		if err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+
+		assertEqual(t, len(body), len(resp.RawBody))
+	})
+
+	t.Run("Rest of the body is drained", func(t *testing.T) {
+		var conns atomic.Int32
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(strings.Repeat("x", size)))
+		}))
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				conns.Add(1)
+			}
+		}
+		server.Start()
+		defer server.Close()
+
+		client := NewGenericClient[User](WithMaxErrorBodyBytes[User](10))
+
+		// Short remainders are discarded and the connection is reused
+		for range 2 {
+			_, _ = client.Get(server.URL + "?size=1000")
+		}
+		assertEqual(t, int32(1), conns.Load())
+
+		// Remainders beyond the drain limit close the connection, so the next request dials
+		_, _ = client.Get(server.URL + "?size=" + strconv.Itoa(16*maxErrorBodyDrainBytes))
+		_, _ = client.Get(server.URL + "?size=1000")
+		assertEqual(t, int32(2), conns.Load())
+	})
+}
+
+func TestGenericClient_WithRetryStrategyFunc(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	var delays []int
+	fibonacci := func(attempt int) time.Duration {
+		delays = append(delays, attempt)
+		a, b := 1, 1
+		for i := 0; i < attempt; i++ {
+			a, b = b, a+b
+		}
+		return time.Duration(a) * time.Millisecond
+	}
+
+	client := NewGenericClient[User](
+		WithRetryStrategy[User](FixedDelayStrategy),
+		WithRetryStrategyFunc[User](fibonacci),
+		WithMaxRetries[User](3),
+	)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+
+	assertEqual(t, 1, resp.Data.ID)
+	// The custom strategy is used for every retry instead of the named strategy
+	assertEqual(t, []int{0, 1}, delays)
+}
+
+type apiError struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+func (e apiError) Error() string {
+	return e.Code + ": " + e.Reason
+}
+
+var errNotFoundSentinel = apiError{Code: "not_found", Reason: "user does not exist"}
+
+func TestGenericClient_WithErrorDecoder(t *testing.T) {
+	errQuotaExceeded := errors.New("quota exceeded")
+	decoder := func(statusCode int, body []byte, headers http.Header) error {
+		var payload struct {
+			Errors []struct {
+				Code string `json:"code"`
+			} `json:"errors"`
+		}
+		if json.Unmarshal(body, &payload) != nil || len(payload.Errors) == 0 {
+			return nil
+		}
+
+		if payload.Errors[0].Code == "QUOTA" {
+			return fmt.Errorf("request %s: %w", headers.Get("X-Request-Id"), errQuotaExceeded)
+		}
+
+		return fmt.Errorf("http %d: %s", statusCode, payload.Errors[0].Code)
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "Mapped code", body: `{"errors":[{"code":"QUOTA"}]}`, wantErr: "request req-42: quota exceeded"},
+		{name: "Nested code", body: `{"errors":[{"code":"INVALID"},{"code":"OTHER"}]}`, wantErr: "http 429: INVALID"},
+		{name: "Fallback to default", body: `{"message":"slow down"}`, wantErr: "slow down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewGenericClient[User](
+				WithHTTPClient[User](&capturingClient{
+					statusCode: http.StatusTooManyRequests,
+					body:       tt.body,
+					header:     http.Header{"X-Request-Id": {"req-42"}},
+				}),
+				WithErrorDecoder[User](decoder),
+			)
+
+			_, err := client.Get("http://example.com/users/1")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	client := NewGenericClient[User](
+		WithHTTPClient[User](&capturingClient{statusCode: http.StatusTooManyRequests, body: `{"errors":[{"code":"QUOTA"}]}`}),
+		WithErrorDecoder[User](decoder),
+	)
+	_, err := client.Get("http://example.com/users/1")
+	if !errors.Is(err, errQuotaExceeded) {
+		t.Errorf("Expected errors.Is to match the decoder error, got %v", err)
+	}
+}
+
+func TestGenericClient_WithErrorType(t *testing.T) {
+	body := `{"code":"not_found","reason":"user does not exist"}`
+	client := NewGenericClient[User](
+		WithHTTPClient[User](&capturingClient{statusCode: http.StatusNotFound, body: body}),
+		WithErrorType[User, apiError](),
+	)
+
+	_, err := client.Get("http://example.com/users/1")
+
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) {
+		t.Fatalf("Expected *ResponseError, got %T: %v", err, err)
+	}
+	assertEqual(t, http.StatusNotFound, respErr.StatusCode())
+	assertEqual(t, "http 404: not_found: user does not exist", err.Error())
+
+	var byValue apiError
+	if !errors.As(err, &byValue) {
+		t.Fatal("Expected errors.As to find apiError")
+	}
+	assertEqual(t, "not_found", byValue.Code)
+
+	var byPointer *apiError
+	if !errors.As(err, &byPointer) {
+		t.Fatal("Expected errors.As to find *apiError")
+	}
+	assertEqual(t, "user does not exist", byPointer.Reason)
+
+	// The default structured error stays available
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatal("Expected errors.As to find *ErrorResponse")
+	}
+	assertEqual(t, http.StatusNotFound, errResp.StatusCode)
+
+	// errors.Is compares against the decoded value
+	if !errors.Is(err, errNotFoundSentinel) {
+		t.Error("Expected errors.Is to match the decoded value")
+	}
+
+	t.Run("Undecodable body", func(t *testing.T) {
+		client := NewGenericClient[User](
+			WithHTTPClient[User](&capturingClient{statusCode: http.StatusBadGateway, body: "<html>Bad Gateway</html>"}),
+			WithErrorType[User, apiError](),
+		)
+
+		_, err := client.Get("http://example.com/users/1")
+
+		var respErr *ResponseError
+		if !errors.As(err, &respErr) {
+			t.Fatalf("Expected *ResponseError, got %T: %v", err, err)
+		}
+
+		var decoded *apiError
+		if errors.As(err, &decoded) {
+			t.Error("Expected no decoded value for a non-JSON body")
+		}
+		assertEqual(t, "http 502: <html>Bad Gateway</html>", err.Error())
+	})
+
+	t.Run("Default error type", func(t *testing.T) {
+		client := NewGenericClient[User](WithHTTPClient[User](&capturingClient{statusCode: http.StatusNotFound, body: body}))
+
+		_, err := client.Get("http://example.com/users/1")
+		if _, ok := err.(*ErrorResponse); !ok {
+			t.Errorf("Expected *ErrorResponse by default, got %T", err)
+		}
+	})
+}
+
+func TestSetDefaultClientConfig(t *testing.T) {
+	t.Cleanup(func() { SetDefaultClientConfig(nil) })
+
+	defaults := NewClientBuilder().
+		WithTimeout(42 * time.Second).
+		WithMaxRetries(7).
+		WithMaxRetriesForStatus(map[int]int{http.StatusTooManyRequests: 9})
+	SetDefaultClientConfig(defaults)
+
+	// Changes to the builder after the call have no effect
+	defaults.WithTimeout(time.Second)
+
+	inherited := NewGenericClient[User]().httpClient.(*http.Client)
+	assertEqual(t, 42*time.Second, attemptTimeout(t, inherited))
+	assertEqual(t, 7, inherited.Transport.(*retryTransport).MaxRetries)
+	assertEqual(t, 9, inherited.Transport.(*retryTransport).maxRetriesForStatus[http.StatusTooManyRequests])
+
+	// Options passed to NewGenericClient override the defaults
+	overridden := NewGenericClient[User](WithTimeout[User](5 * time.Second)).httpClient.(*http.Client)
+	assertEqual(t, 5*time.Second, attemptTimeout(t, overridden))
+	assertEqual(t, 7, overridden.Transport.(*retryTransport).MaxRetries)
+
+	SetDefaultClientConfig(nil)
+	restored := NewGenericClient[User]().httpClient.(*http.Client)
+	assertEqual(t, DefaultTimeout, attemptTimeout(t, restored))
+	assertEqual(t, DefaultMaxRetries, restored.Transport.(*retryTransport).MaxRetries)
+}
+
+func TestGenericClient_WithExpectedContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expectedErr string
+	}{
+		{name: "Matching type", contentType: "application/json", body: `{"id":1}`},
+		{name: "Parameters and case are ignored", contentType: "Application/JSON; charset=utf-8", body: `{"id":1}`},
+		{name: "Alternative type", contentType: "application/problem+json", body: `{"id":1}`},
+		{name: "HTML page", contentType: "text/html; charset=utf-8", body: "<html></html>", expectedErr: "expected application/json or application/problem+json, got text/html"},
+		{name: "Missing type", body: `{"id":1}`, expectedErr: "got no content type"},
+		{name: "Empty body is not checked", contentType: "text/html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewGenericClient[User](
+				WithHTTPClient[User](&capturingClient{body: tt.body, header: http.Header{"Content-Type": {tt.contentType}}}),
+				WithExpectedContentType[User]("application/json", " Application/Problem+JSON "),
+			)
+
+			_, err := client.Get("http://example.com/users/1")
+
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("Execute() failed: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrUnexpectedContentType) {
+				t.Fatalf("Expected ErrUnexpectedContentType, got %v", err)
+			}
+
+			if !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Error = %q, want it to contain %q", err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestGenericClient_WithTransport(t *testing.T) {
+	var calls int
+	var seenPaths []string
+	transport := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			seenPaths = append(seenPaths, req.URL.Path)
+			if calls == 1 {
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Body:       io.NopCloser(strings.NewReader(`{"message":"unavailable"}`)),
+					Header:     make(http.Header),
+					Request:    req,
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"id":1,"name":"recorded"}`)),
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Request:    req,
+			}, nil
+		},
+	}
+
+	client := NewGenericClient[User](
+		WithTransport[User](transport),
+		WithTimeout[User](7*time.Second),
+		WithMaxRetries[User](2),
+		WithRetryStrategyFunc[User](func(int) time.Duration { return time.Millisecond }),
+	)
+
+	httpClient, ok := client.httpClient.(*http.Client)
+	if !ok {
+		t.Fatalf("Expected *http.Client, got %T", client.httpClient)
+	}
+	if got := attemptTimeout(t, httpClient); got != 7*time.Second {
+		t.Errorf("Timeout = %v, want 7s", got)
+	}
+
+	resp, err := client.Get("http://example.invalid/users/1")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if resp.Data.Name != "recorded" {
+		t.Errorf("Data.Name = %q, want %q", resp.Data.Name, "recorded")
+	}
+
+	// The failed first attempt is retried through the custom transport
+	if calls != 2 {
+		t.Errorf("Transport calls = %d, want 2", calls)
+	}
+	for _, path := range seenPaths {
+		if path != "/users/1" {
+			t.Errorf("Transport saw path %q, want /users/1", path)
+		}
+	}
+
+	// A nil transport is ignored
+	defaultClient := NewGenericClient[User](WithTransport[User](nil))
+	if defaultClient.transport != nil {
+		t.Error("Expected nil transport to be ignored")
+	}
+}
+
+func TestResponse_BodyReader(t *testing.T) {
+	response := &Response[User]{RawBody: []byte(`{"id":1,"name":"John"}`)}
+
+	// Every reader starts from the beginning of the body
+	for i := 0; i < 2; i++ {
+		body, err := io.ReadAll(response.BodyReader())
+		if err != nil {
+			t.Fatalf("ReadAll() failed: %v", err)
+		}
+
+		assertEqual(t, string(response.RawBody), string(body))
+	}
+
+	var user User
+	if err := json.NewDecoder(response.BodyReader()).Decode(&user); err != nil {
+		t.Fatalf("Decode() failed: %v", err)
+	}
+
+	assertEqual(t, "John", user.Name)
+
+	empty := &Response[User]{}
+	body, err := io.ReadAll(empty.BodyReader())
+	if err != nil {
+		t.Fatalf("ReadAll() on empty body failed: %v", err)
+	}
+
+	assertEqual(t, 0, len(body))
+}
+
+func TestResponse_StatusClassHelpers(t *testing.T) {
+	tests := []struct {
+		statusCode    int
+		isSuccess     bool
+		isRedirect    bool
+		isClientError bool
+		isServerError bool
+	}{
+		{statusCode: http.StatusOK, isSuccess: true},
+		{statusCode: http.StatusNoContent, isSuccess: true},
+		{statusCode: http.StatusMovedPermanently, isRedirect: true},
+		{statusCode: http.StatusNotModified, isRedirect: true},
+		{statusCode: http.StatusBadRequest, isClientError: true},
+		{statusCode: http.StatusTooManyRequests, isClientError: true},
+		{statusCode: http.StatusInternalServerError, isServerError: true},
+		{statusCode: http.StatusServiceUnavailable, isServerError: true},
+		{statusCode: http.StatusContinue},
+		{statusCode: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.statusCode), func(t *testing.T) {
+			response := &Response[User]{StatusCode: tt.statusCode}
+
+			assertEqual(t, tt.isSuccess, response.IsSuccess())
+			assertEqual(t, tt.isRedirect, response.IsRedirect())
+			assertEqual(t, tt.isClientError, response.IsClientError())
+			assertEqual(t, tt.isServerError, response.IsServerError())
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+)
+
+// RPCError is a JSON-RPC 2.0 error object returned by the server in response to CallRPC,
+// e.g. -32601 "Method not found". Use errors.As to inspect it.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"` // Additional information, if any
+}
+
+// Error implements the error interface.
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("json-rpc error %d: %s", e.Code, e.Message)
+}
+
+// rpcRequest is the JSON-RPC 2.0 request envelope.
+type rpcRequest[P any] struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  P      `json:"params"`
+	ID      uint64 `json:"id"`
+}
+
+// rpcResponse is the JSON-RPC 2.0 response envelope.
+type rpcResponse[R any] struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  R               `json:"result"`
+	Error   *RPCError       `json:"error"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// rpcID is the source of JSON-RPC request IDs, unique within the process.
+var rpcID atomic.Uint64
+
+// CallRPC calls a JSON-RPC 2.0 method: it wraps params in a request envelope with a new
+// ID, POSTs it to url with client and returns the result of the response. An error object
+// in the response is returned as an *RPCError. It behaves like ExecuteRaw and counts
+// failures in Stats. Use CallRPCContext to pass a context.
+func CallRPC[P, R any](client *GenericClient[R], url, method string, params P) (R, error) {
+	return CallRPCContext[P, R](context.Background(), client, url, method, params)
+}
+
+// CallRPCContext is like CallRPC with a context for the request.
+func CallRPCContext[P, R any](ctx context.Context, client *GenericClient[R], url, method string, params P) (R, error) {
+	var result R
+
+	id := rpcID.Add(1)
+	body, err := json.Marshal(rpcRequest[P]{JSONRPC: "2.0", Method: method, Params: params, ID: id})
+	if err != nil {
+		return result, fmt.Errorf("marshal JSON-RPC request: %w", err)
+	}
+
+	if client.perRequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.perRequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return result, fmt.Errorf("create JSON-RPC request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	err = client.executeRawWith(req, func(resp *http.Response) error {
+		var bodyReader io.Reader = resp.Body
+		if resp.StatusCode >= 400 {
+			if limit := client.errorBodyLimit(); limit > 0 {
+				bodyReader = io.LimitReader(resp.Body, int64(limit))
+			}
+		}
+
+		data, err := io.ReadAll(bodyReader)
+		if err != nil {
+			return fmt.Errorf("read response body: %w", err)
+		}
+		if resp.StatusCode >= 400 {
+			drainErrorBody(resp.Body)
+		}
+
+		// Servers may send an error object with an HTTP error status, so it is checked first
+		var envelope rpcResponse[R]
+		decodeErr := json.Unmarshal(data, &envelope)
+		if decodeErr == nil && envelope.Error != nil {
+			return envelope.Error
+		}
+
+		if resp.StatusCode >= 400 {
+			return client.handleErrorResponse(resp.StatusCode, data, resp.Header)
+		}
+
+		if decodeErr != nil {
+			return fmt.Errorf("unmarshal JSON-RPC response: %w", decodeErr)
+		}
+
+		if string(envelope.ID) != strconv.FormatUint(id, 10) {
+			return fmt.Errorf("JSON-RPC response id %s does not match request id %d", envelope.ID, id)
+		}
+
+		result = envelope.Result
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,82 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCallRPC(t *testing.T) {
+	type sumParams struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			JSONRPC string          `json:"jsonrpc"`
+			Method  string          `json:"method"`
+			Params  sumParams       `json:"params"`
+			ID      json.RawMessage `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.JSONRPC != "2.0" || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch req.Method {
+		case "sum":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":` + strconv.Itoa(req.Params.A+req.Params.B) + `,"id":` + string(req.ID) + `}`))
+		case "wrong_id":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":1,"id":0}`))
+		case "unavailable":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"forbidden"}`))
+		case "bad_request":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(strings.Repeat("x", DefaultMaxErrorBodyBytes+100)))
+		default:
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found","data":"` + req.Method + `"},"id":` + string(req.ID) + `}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[int]()
+
+	result, err := CallRPC[sumParams, int](client, server.URL, "sum", sumParams{A: 2, B: 3})
+	if err != nil {
+		t.Fatalf("CallRPC() failed: %v", err)
+	}
+	assertEqual(t, 5, result)
+
+	_, err = CallRPC[sumParams, int](client, server.URL, "subtract", sumParams{})
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("Expected *RPCError, got %v", err)
+	}
+	assertEqual(t, -32601, rpcErr.Code)
+	assertEqual(t, "Method not found", rpcErr.Message)
+	assertEqual(t, `"subtract"`, string(rpcErr.Data))
+
+	_, err = CallRPC[sumParams, int](client, server.URL, "unavailable", sumParams{})
+	var apiErr *ErrorResponse
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a 403 ErrorResponse, got %v", err)
+	}
+
+	// Error bodies are limited like those read by Execute
+	_, err = CallRPC[sumParams, int](client, server.URL, "bad_request", sumParams{})
+	if !errors.As(err, &apiErr) || len(apiErr.Message) != DefaultMaxErrorBodyBytes {
+		t.Errorf("Expected a 400 ErrorResponse with a truncated body, got %v", err)
+	}
+
+	_, err = CallRPC[sumParams, int](client, server.URL, "wrong_id", sumParams{})
+	if err == nil || !strings.Contains(err.Error(), "does not match request id") {
+		t.Errorf("Expected an id mismatch error, got %v", err)
+	}
+}
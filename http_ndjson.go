@@ -0,0 +1,87 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// StreamNDJSON sends the request and decodes the newline-delimited JSON (JSON lines)
+// response incrementally, emitting each decoded value of type T on the first channel
+// without buffering the whole stream. Both channels are closed when the stream ends.
+// At most one error is sent on the error channel: a request or decoding error, an
+// *ErrorResponse (or *ResponseError) for error statuses, or the context error when the
+// request context is done, which is how callers stop consuming the stream early.
+//
+// The client timeout also applies to reading the stream, so long streams need a client
+// with a suitable timeout.
+func (c *GenericClient[T]) StreamNDJSON(req *http.Request) (<-chan T, <-chan error) {
+	values := make(chan T)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(values)
+		defer close(errs)
+
+		if err := c.streamNDJSON(req, values); err != nil {
+			errs <- err
+		}
+	}()
+
+	return values, errs
+}
+
+// streamNDJSON performs the request and sends the decoded values to values.
+func (c *GenericClient[T]) streamNDJSON(req *http.Request, values chan<- T) error {
+	if c.closed.Load() {
+		return ErrClientClosed
+	}
+
+	req, err := c.prepareRequest(req)
+	if err != nil {
+		return err
+	}
+
+	if req.Header.Get("Accept") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept", "application/x-ndjson")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return c.errorFromResponse(resp)
+	}
+
+	c.trackProgress(resp)
+
+	ctx := req.Context()
+	decoder := json.NewDecoder(resp.Body)
+
+	for {
+		var value T
+		if err := decoder.Decode(&value); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+
+			return fmt.Errorf("decode ndjson value: %w", err)
+		}
+
+		select {
+		case values <- value:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGenericClient_StreamNDJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, "application/x-ndjson", r.Header.Get("Accept"))
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte("{\"id\":1,\"name\":\"John\"}\n\n{\"id\":2,\"name\":\"Jane\"}\n{\"id\":3,\"name\":\"Jack\"}\n"))
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[User]()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() failed: %v", err)
+	}
+
+	values, errs := client.StreamNDJSON(req)
+
+	var names []string
+	for user := range values {
+		names = append(names, user.Name)
+	}
+
+	if err := <-errs; err != nil {
+		t.Fatalf("StreamNDJSON() failed: %v", err)
+	}
+
+	assertEqual(t, []string{"John", "Jane", "Jack"}, names)
+}
+
+func TestGenericClient_StreamNDJSON_Errors(t *testing.T) {
+	t.Run("Decoding error after valid values", func(t *testing.T) {
+		client := NewGenericClient[User](WithHTTPClient[User](&capturingClient{body: "{\"id\":1}\n{not json}\n"}))
+
+		values, errs := client.StreamNDJSON(httptest.NewRequest(http.MethodGet, "http://example.com/users", nil))
+
+		var count int
+		for range values {
+			count++
+		}
+
+		err := <-errs
+		if err == nil || !strings.Contains(err.Error(), "decode ndjson value") {
+			t.Fatalf("Expected decoding error, got %v", err)
+		}
+		assertEqual(t, 1, count)
+	})
+
+	t.Run("Error status", func(t *testing.T) {
+		client := NewGenericClient[User](WithHTTPClient[User](&capturingClient{statusCode: http.StatusForbidden}))
+
+		values, errs := client.StreamNDJSON(httptest.NewRequest(http.MethodGet, "http://example.com/users", nil))
+		for range values {
+			t.Error("Expected no values for an error response")
+		}
+
+		var errResp *ErrorResponse
+		if err := <-errs; !errors.As(err, &errResp) {
+			t.Fatalf("Expected *ErrorResponse, got %v", err)
+		}
+	})
+
+	t.Run("Context cancellation stops the stream", func(t *testing.T) {
+		client := NewGenericClient[User](WithHTTPClient[User](&capturingClient{body: "{\"id\":1}\n{\"id\":2}\n"}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/users", nil).WithContext(ctx)
+
+		values, errs := client.StreamNDJSON(req)
+		<-values
+		cancel()
+
+		// The producer is blocked sending the second value until it notices the cancellation
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+
+		if _, ok := <-values; ok {
+			t.Error("Expected the values channel to be closed")
+		}
+	})
+}
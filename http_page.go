@@ -0,0 +1,158 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// DefaultPageItemsField is the default field of a list response holding the items of a page.
+	DefaultPageItemsField = "data"
+
+	// DefaultPageTotalField is the default field of a list response holding the total number of items.
+	DefaultPageTotalField = "total"
+)
+
+// Page is a page of a list response, such as {"data":[...],"total":42,"page":2}, as returned
+// by GetPage. The fields holding the items and the total are set with WithPageFields.
+type Page[T any] struct {
+	// Items are the decoded items of the page.
+	Items []T
+
+	// Total is the total number of items across all pages, or -1 when the response has none.
+	Total int
+
+	// Metadata holds the other top-level fields of the response, e.g. "page" or "next_cursor",
+	// to be decoded by the caller.
+	Metadata map[string]json.RawMessage
+
+	StatusCode int
+	Headers    http.Header
+}
+
+// WithPageFields sets the fields of list responses that GetPage reads the items and the
+// total number of items from, since APIs differ, e.g. "items" and "count". Nested fields
+// are separated by dots, e.g. "meta.total". Empty names keep the defaults, "data" and "total".
+func WithPageFields[T any](itemsField, totalField string) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.pageItemsField = itemsField
+		c.pageTotalField = totalField
+	}
+}
+
+// GetPage performs a GET request for a page of a list response and decodes it into a Page
+// of the item type T of the client, so paginated responses are handled the same way for
+// every API. It behaves like ExecuteRaw and counts failures in Stats. A response without
+// the items field fails, while a missing total sets Total to -1.
+func (c *GenericClient[T]) GetPage(url string) (*Page[T], error) {
+	return c.GetPageContext(context.Background(), url)
+}
+
+// GetPageContext is like GetPage with a context for the request.
+func (c *GenericClient[T]) GetPageContext(ctx context.Context, url string) (*Page[T], error) {
+	if c.perRequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.perRequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create GET request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var page *Page[T]
+	err = c.executeRawWith(req, func(resp *http.Response) error {
+		page, err = c.decodePage(resp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// decodePage decodes a page from the response of GetPage.
+func (c *GenericClient[T]) decodePage(resp *http.Response) (*Page[T], error) {
+	if resp.StatusCode >= 400 {
+		return nil, c.errorFromResponse(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal page json: %w", err)
+	}
+
+	itemsField := c.pageItemsField
+	if itemsField == "" {
+		itemsField = DefaultPageItemsField
+	}
+
+	totalField := c.pageTotalField
+	if totalField == "" {
+		totalField = DefaultPageTotalField
+	}
+
+	page := &Page[T]{
+		Total:      -1,
+		Metadata:   make(map[string]json.RawMessage, len(fields)),
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
+	}
+
+	items, ok := lookupJSONField(fields, itemsField)
+	if !ok {
+		return nil, fmt.Errorf("page items field %q not found in response", itemsField)
+	}
+	if err := json.Unmarshal(items, &page.Items); err != nil {
+		return nil, fmt.Errorf("unmarshal page items %q: %w", itemsField, err)
+	}
+
+	if total, ok := lookupJSONField(fields, totalField); ok {
+		if err := json.Unmarshal(total, &page.Total); err != nil {
+			return nil, fmt.Errorf("unmarshal page total %q: %w", totalField, err)
+		}
+	}
+
+	// The items are not repeated in the metadata when they are a top-level field
+	for name, value := range fields {
+		if name != itemsField {
+			page.Metadata[name] = value
+		}
+	}
+
+	return page, nil
+}
+
+// lookupJSONField returns the raw value of a field of a JSON object, following nested
+// objects for dot-separated paths such as "meta.total". Null values are reported as missing.
+func lookupJSONField(fields map[string]json.RawMessage, path string) (json.RawMessage, bool) {
+	name, rest, nested := strings.Cut(path, ".")
+
+	value, ok := fields[name]
+	if !ok || string(value) == "null" {
+		return nil, false
+	}
+
+	if !nested {
+		return value, true
+	}
+
+	var inner map[string]json.RawMessage
+	if err := json.Unmarshal(value, &inner); err != nil {
+		return nil, false
+	}
+
+	return lookupJSONField(inner, rest)
+}
@@ -0,0 +1,79 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGenericClient_GetPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users":
+			_, _ = w.Write([]byte(`{"data":[{"id":1,"name":"John"},{"id":2,"name":"Jane"}],"total":42,"page":2}`))
+		case "/items":
+			_, _ = w.Write([]byte(`{"items":[{"id":3,"name":"Joe"}],"meta":{"count":7},"next_cursor":"abc"}`))
+		case "/no-total":
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		case "/no-items":
+			_, _ = w.Write([]byte(`{"results":[],"total":0}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	t.Run("Default fields", func(t *testing.T) {
+		page, err := NewGenericClient[User]().GetPage(server.URL + "/users")
+		if err != nil {
+			t.Fatalf("GetPage() failed: %v", err)
+		}
+
+		assertEqual(t, []User{{ID: 1, Name: "John"}, {ID: 2, Name: "Jane"}}, page.Items)
+		assertEqual(t, 42, page.Total)
+		assertEqual(t, http.StatusOK, page.StatusCode)
+		assertEqual(t, map[string]json.RawMessage{"total": json.RawMessage(`42`), "page": json.RawMessage(`2`)}, page.Metadata)
+	})
+
+	t.Run("Configured fields", func(t *testing.T) {
+		client := NewGenericClient[User](WithPageFields[User]("items", "meta.count"))
+		page, err := client.GetPage(server.URL + "/items")
+		if err != nil {
+			t.Fatalf("GetPage() failed: %v", err)
+		}
+
+		assertEqual(t, []User{{ID: 3, Name: "Joe"}}, page.Items)
+		assertEqual(t, 7, page.Total)
+		assertEqual(t, `"abc"`, string(page.Metadata["next_cursor"]))
+	})
+
+	t.Run("Missing total", func(t *testing.T) {
+		page, err := NewGenericClient[User]().GetPage(server.URL + "/no-total")
+		if err != nil {
+			t.Fatalf("GetPage() failed: %v", err)
+		}
+
+		assertEqual(t, 0, len(page.Items))
+		assertEqual(t, -1, page.Total)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		client := NewGenericClient[User]()
+
+		_, err := client.GetPage(server.URL + "/no-items")
+		if err == nil || !strings.Contains(err.Error(), `page items field "data" not found`) {
+			t.Errorf("Expected a missing items error, got %v", err)
+		}
+
+		_, err = client.GetPage(server.URL + "/missing")
+		var apiErr *ErrorResponse
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected a 404 ErrorResponse, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package httpx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// progressInterval is the minimum time between two progress reports of a response body.
+const progressInterval = 100 * time.Millisecond
+
+// ProgressFunc is a function that is called as a response body is read, with the number of
+// bytes read so far and the total size from Content-Length, or -1 when it is unknown.
+type ProgressFunc func(bytesRead, totalBytes int64)
+
+// WithProgress sets a function that reports the progress of reading response bodies, e.g.
+// to drive a progress bar for large downloads. It is called at most every 100ms while the
+// body streams, and once more when the body is read completely. It applies to the bodies of
+// Execute, ExecuteRaw and the helpers built on them, and to StreamNDJSON; with ExecuteRaw
+// it reports as the caller reads the body. totalBytes is -1 when the response has no
+// Content-Length, e.g. for chunked or decompressed responses. Pass nil to disable it
+// (default behavior).
+func WithProgress[T any](progress ProgressFunc) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.progress = progress
+	}
+}
+
+// trackProgress wraps the body of resp to report its reading progress, if enabled.
+func (c *GenericClient[T]) trackProgress(resp *http.Response) {
+	if c.progress == nil || resp.Body == nil || resp.Body == http.NoBody {
+		return
+	}
+
+	resp.Body = &progressBody{
+		ReadCloser: resp.Body,
+		progress:   c.progress,
+		total:      resp.ContentLength,
+	}
+}
+
+// progressBody is a response body that reports the bytes read at intervals.
+type progressBody struct {
+	io.ReadCloser
+	progress   ProgressFunc
+	total      int64
+	read       int64
+	reported   int64 // Bytes read at the last report
+	lastReport time.Time
+	done       bool
+}
+
+func (b *progressBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+
+	switch {
+	case errors.Is(err, io.EOF):
+		// The final report is sent once, unless the last report already covered all bytes
+		if !b.done {
+			b.done = true
+			if b.lastReport.IsZero() || b.read != b.reported {
+				b.report()
+			}
+		}
+	case n > 0 && time.Since(b.lastReport) >= progressInterval:
+		b.report()
+	}
+
+	return n, err
+}
+
+func (b *progressBody) report() {
+	b.lastReport = time.Now()
+	b.reported = b.read
+	b.progress(b.read, b.total)
+}
@@ -0,0 +1,72 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenericClient_WithProgress(t *testing.T) {
+	payload := `{"id":1,"name":"` + strings.Repeat("x", 64<<10) + `"}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stream" {
+			// Chunked without Content-Length, with a pause longer than the report interval
+			_, _ = w.Write([]byte("first chunk,"))
+			w.(http.Flusher).Flush()
+			time.Sleep(progressInterval + 20*time.Millisecond)
+			_, _ = w.Write([]byte("second chunk"))
+			return
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	type report struct{ read, total int64 }
+	var reports []report
+	client := NewGenericClient[User](WithProgress[User](func(bytesRead, totalBytes int64) {
+		reports = append(reports, report{bytesRead, totalBytes})
+	}))
+
+	t.Run("Execute", func(t *testing.T) {
+		reports = nil
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		assertEqual(t, 1, resp.Data.ID)
+
+		if len(reports) == 0 {
+			t.Fatal("Expected progress reports")
+		}
+		total := int64(len(payload))
+		assertEqual(t, report{total, total}, reports[len(reports)-1])
+		for i := 1; i < len(reports); i++ {
+			if reports[i].read < reports[i-1].read {
+				t.Errorf("Expected non-decreasing progress, got %v", reports)
+			}
+		}
+	})
+
+	t.Run("ExecuteRaw streaming", func(t *testing.T) {
+		reports = nil
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/stream", nil)
+		resp, err := client.ExecuteRaw(req)
+		if err != nil {
+			t.Fatalf("ExecuteRaw() failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		_, _ = resp.Body.Read(make([]byte, 1))
+		assertEqual(t, "first chunk,second chunk", string(body))
+
+		assertEqual(t, []report{{12, -1}, {24, -1}}, reports)
+	})
+}
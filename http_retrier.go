@@ -1,18 +1,50 @@
 package httpx
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"math/rand"
+	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 )
 
 var ErrAllRetriesFailed = errors.New("all retry attempts failed")
 
+// ErrRetryBudgetExhausted is returned when a request failed and was not retried because
+// the client's retry budget did not allow another retry.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// ErrRetryAfterTooLong is returned when a 429 or 503 response asks with its Retry-After
+// header for a longer wait than the client's maximum, in which case it is not retried.
+var ErrRetryAfterTooLong = errors.New("retry-after exceeds maximum wait")
+
+// ErrResponseTooSlow is returned when a request, including all of its retries and retry
+// delays, did not complete within the maximum response time of the client.
+var ErrResponseTooSlow = errors.New("response too slow")
+
+// ErrAttemptTimeout is returned when the last attempt of a request did not complete within
+// the per-attempt timeout of the client. Attempts that time out are retried.
+var ErrAttemptTimeout = errors.New("attempt timed out")
+
+// ErrBodyReadTimeout is returned by reads of a response body that received no data within
+// the body read timeout of the client, e.g. because the server stalled after the headers.
+var ErrBodyReadTimeout = errors.New("response body read timed out")
+
 // RetryStrategy defines the function signature for different retry strategies
 type RetryStrategy func(attempt int) time.Duration
 
@@ -43,6 +75,37 @@ func ExponentialBackoff(base, maxDelay time.Duration) RetryStrategy {
 	}
 }
 
+// ExponentialBackoffWithMultiplier returns a RetryStrategy like ExponentialBackoff whose
+// delays grow by the given multiplier instead of doubling: base * multiplier^attempt,
+// capped at maxDelay. For example, a multiplier of 1.5 gives 1s, 1.5s, 2.25s, ...
+// Multipliers below 1 (and NaN or infinite values) fall back to DefaultRetryMultiplier.
+func ExponentialBackoffWithMultiplier(base, maxDelay time.Duration, multiplier float64) RetryStrategy {
+	if !isValidRetryMultiplier(multiplier) {
+		multiplier = DefaultRetryMultiplier
+	}
+
+	return func(attempt int) time.Duration {
+		// Same as ExponentialBackoff: the first attempt returns base even if it exceeds maxDelay
+		if attempt == 0 && base > maxDelay {
+			return base
+		}
+
+		// Compute in floating point and cap before converting back, which also
+		// covers overflow for large attempts
+		delay := float64(base) * math.Pow(multiplier, float64(attempt))
+		if delay >= float64(maxDelay) || math.IsInf(delay, 0) || math.IsNaN(delay) {
+			return maxDelay
+		}
+
+		return time.Duration(delay)
+	}
+}
+
+// isValidRetryMultiplier reports whether multiplier can be used for exponential backoff.
+func isValidRetryMultiplier(multiplier float64) bool {
+	return multiplier >= 1 && !math.IsInf(multiplier, 0)
+}
+
 // FixedDelay returns a RetryStrategy that provides a constant delay
 // for each retry attempt.
 func FixedDelay(delay time.Duration) RetryStrategy {
@@ -54,7 +117,12 @@ func FixedDelay(delay time.Duration) RetryStrategy {
 // JitterBackoff returns a RetryStrategy that adds a random jitter
 // to the exponential backoff delay calculated using base and maxDelay.
 func JitterBackoff(base, maxDelay time.Duration) RetryStrategy {
-	expBackoff := ExponentialBackoff(base, maxDelay)
+	return withJitter(ExponentialBackoff(base, maxDelay))
+}
+
+// withJitter returns a RetryStrategy that adds a random jitter of up to half
+// of the delay calculated by expBackoff.
+func withJitter(expBackoff RetryStrategy) RetryStrategy {
 	return func(attempt int) time.Duration {
 		baseDelay := expBackoff(attempt)
 
@@ -71,10 +139,612 @@ type retryTransport struct {
 	RetryStrategy RetryStrategy     // The strategy function to calculate delay
 	MaxRetries    int
 	logger        *slog.Logger // Optional logger for retry operations (nil = no logging)
+
+	// maxRetriesForStatus overrides MaxRetries for responses with a matching status code
+	maxRetriesForStatus map[int]int
+
+	// nonRetryableStatus holds status codes returned without retrying even if 5xx or 429
+	nonRetryableStatus map[int]bool
+
+	// maxDelay caps delays requested by the server via Retry-After (0 = no cap)
+	maxDelay time.Duration
+
+	// respectRetryAfter lengthens retry delays to the Retry-After header of 429 and 503 responses
+	respectRetryAfter bool
+
+	// strategyCapping reports the delay of RetryStrategy before its maximum delay capped it,
+	// for strategies built from the client configuration (nil = unknown)
+	strategyCapping func(attempt int) (uncapped time.Duration, capped bool)
+
+	// absoluteMaxDelay caps every retry delay, whatever its source (0 = no cap)
+	absoluteMaxDelay time.Duration
+
+	// retryAfterMaxWait is the longest Retry-After delay honored, instead of maxDelay; longer ones
+	// stop retrying (0 = no limit)
+	retryAfterMaxWait time.Duration
+
+	// deadlineHeader is the header that carries the remaining time of the request context deadline
+	deadlineHeader string
+
+	// attemptsHeader is the response header set to the number of attempts of the request (empty = none)
+	attemptsHeader string
+
+	// defaultHeaders are set on requests that do not carry them (nil = none)
+	defaultHeaders http.Header
+
+	// budget limits retries across all requests of the client (nil = unlimited)
+	budget *retryBudget
+
+	// retryableError reports whether a transport error is retried (nil = DefaultRetryableError)
+	retryableError func(error) bool
+
+	// noConnectionResetRetry stops connection resets from being retried regardless of retryableError
+	noConnectionResetRetry bool
+
+	// connLifetime tracks when connections dialed with withConnLifetime are in use, so that
+	// they are retired once expired and idle
+	connLifetime bool
+
+	// maxResponseTime bounds the whole retry sequence of a request (0 = no limit)
+	maxResponseTime time.Duration
+
+	// perAttemptTimeout bounds each attempt, including reading the response body (0 = no limit)
+	perAttemptTimeout time.Duration
+
+	// bodyReadTimeout bounds each read of the returned response body (0 = no limit)
+	bodyReadTimeout time.Duration
+
+	// maxRetryBodyBytes is the largest body without GetBody buffered for retries (0 = none)
+	maxRetryBodyBytes int
+}
+
+// DefaultRetryableError is the default classification of transport errors used by the
+// retry logic. It reports false for errors that a retry cannot fix: context cancellation
+// and deadlines, certificate verification failures and TLS handshakes with a server that
+// does not speak TLS. All other errors, such as connection resets or timeouts, are retried.
+func DefaultRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var (
+		certVerificationErr *tls.CertificateVerificationError
+		recordHeaderErr     tls.RecordHeaderError
+		unknownAuthorityErr x509.UnknownAuthorityError
+		certInvalidErr      x509.CertificateInvalidError
+		hostnameErr         x509.HostnameError
+	)
+
+	switch {
+	case errors.As(err, &certVerificationErr),
+		errors.As(err, &recordHeaderErr),
+		errors.As(err, &unknownAuthorityErr),
+		errors.As(err, &certInvalidErr),
+		errors.As(err, &hostnameErr):
+		return false
+	}
+
+	return true
+}
+
+// IsConnectionReset reports whether err is a connection reset by the peer: ECONNRESET or
+// a broken pipe, as happens when the server drops an idle keep-alive connection just as it
+// is reused. Such errors are transient and sending the request again on a new connection
+// usually succeeds.
+func IsConnectionReset(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
+
+// isRetryableError reports whether a transport error should be retried. reusedConn reports
+// whether the attempt was sent on a pooled connection. Connection resets, and EOF on a reused
+// connection, are retried unless disabled, even if retryableError rejects them.
+func (r *retryTransport) isRetryableError(err error, reusedConn bool) bool {
+	if !r.noConnectionResetRetry {
+		if IsConnectionReset(err) || reusedConn && (errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)) {
+			return true
+		}
+	}
+
+	if r.retryableError != nil {
+		return r.retryableError(err)
+	}
+
+	return DefaultRetryableError(err)
+}
+
+// retryBudgetBuckets is the number of one-second buckets of the retry budget window.
+const retryBudgetBuckets = 10
+
+// retryBudgetWindow is the sliding window over which the retry budget is computed.
+const retryBudgetWindow = retryBudgetBuckets * time.Second
+
+// retryBudget limits the number of retries relative to the number of requests sent.
+// Within a sliding window, retries are allowed while they stay below
+// minPerSecond*window + ratio*requests. The counters are kept in one-second buckets,
+// so the budget is shared safely by all requests of a client.
+type retryBudget struct {
+	mu           sync.Mutex
+	ratio        float64
+	minPerSecond int
+	buckets      [retryBudgetBuckets]retryBudgetBucket
+}
+
+// retryBudgetBucket holds the counters of one second of the retry budget window.
+type retryBudgetBucket struct {
+	second   int64
+	requests int
+	retries  int
+}
+
+// newRetryBudget creates a retry budget allowing ratio retries per request on top of
+// minPerSecond retries per second. It returns nil, meaning no budget, when ratio is
+// negative or both values are zero or negative.
+func newRetryBudget(ratio float64, minPerSecond int) *retryBudget {
+	if ratio < 0 || (ratio == 0 && minPerSecond <= 0) {
+		return nil
+	}
+
+	if minPerSecond < 0 {
+		minPerSecond = 0
+	}
+
+	return &retryBudget{ratio: ratio, minPerSecond: minPerSecond}
+}
+
+// bucket returns the bucket for now, resetting it if it holds counters from an older second.
+// The caller must hold b.mu.
+func (b *retryBudget) bucket(now time.Time) *retryBudgetBucket {
+	second := now.Unix()
+	bucket := &b.buckets[int(second%int64(len(b.buckets)))]
+	if bucket.second != second {
+		*bucket = retryBudgetBucket{second: second}
+	}
+
+	return bucket
+}
+
+// recordRequest counts a request sent at now.
+func (b *retryBudget) recordRequest(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bucket(now).requests++
+}
+
+// allowRetry reports whether a retry at now fits in the budget and, if so, counts it.
+func (b *retryBudget) allowRetry(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current := b.bucket(now)
+	oldest := now.Unix() - int64(len(b.buckets)) + 1
+
+	var requests, retries int
+	for _, bucket := range b.buckets {
+		if bucket.second >= oldest {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+
+	allowed := float64(b.minPerSecond)*retryBudgetWindow.Seconds() + b.ratio*float64(requests)
+	if float64(retries) >= allowed {
+		return false
+	}
+
+	current.retries++
+
+	return true
+}
+
+// withDefaultHeaders returns a copy of req carrying the default headers it does not set
+// itself. The request is returned unchanged when it already sets all of them.
+func (r *retryTransport) withDefaultHeaders(req *http.Request) *http.Request {
+	var headerReq *http.Request
+	for key, values := range r.defaultHeaders {
+		if len(req.Header.Values(key)) > 0 {
+			continue
+		}
+
+		if headerReq == nil {
+			headerReq = req.WithContext(req.Context())
+			headerReq.Header = req.Header.Clone()
+			if headerReq.Header == nil {
+				headerReq.Header = make(http.Header)
+			}
+		}
+
+		headerReq.Header[key] = values
+	}
+
+	if headerReq == nil {
+		return req
+	}
+
+	return headerReq
+}
+
+// withDeadlineHeader returns a copy of req carrying the remaining time until the request
+// context deadline in the configured deadline header. The request is returned unchanged
+// when no header is configured, the context has no deadline, or the deadline has passed.
+func (r *retryTransport) withDeadlineHeader(req *http.Request) *http.Request {
+	if r.deadlineHeader == "" {
+		return req
+	}
+
+	deadline, ok := req.Context().Deadline()
+	if !ok {
+		return req
+	}
+
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return req
+	}
+
+	attemptReq := req.WithContext(req.Context())
+	attemptReq.Header = req.Header.Clone()
+	if attemptReq.Header == nil {
+		attemptReq.Header = make(http.Header)
+	}
+
+	attemptReq.Header.Set(r.deadlineHeader, formatDeadlineHeader(r.deadlineHeader, remaining))
+
+	return attemptReq
+}
+
+// formatDeadlineHeader formats the remaining time for the given deadline header.
+// The gRPC "grpc-timeout" header uses the gRPC timeout format (e.g. "1500m"),
+// any other header carries the remaining time in whole milliseconds (e.g. "1500").
+func formatDeadlineHeader(name string, remaining time.Duration) string {
+	millis := remaining.Milliseconds()
+
+	if strings.EqualFold(name, "grpc-timeout") {
+		// gRPC timeout values are limited to 8 digits
+		if millis < 100_000_000 {
+			return strconv.FormatInt(millis, 10) + "m"
+		}
+
+		return strconv.FormatInt(int64(remaining/time.Second), 10) + "S"
+	}
+
+	return strconv.FormatInt(millis, 10)
+}
+
+// Delay sources reported in retry logs, describing why a retry delay was chosen.
+const (
+	delaySourceStrategy   = "strategy"    // Delay computed by the retry strategy
+	delaySourceRetryAfter = "retry-after" // Delay requested by the server via Retry-After
+	delaySourceCapped     = "capped"      // Delay capped at the maximum or absolute maximum delay
+)
+
+// retryDelay returns the delay to wait before the next attempt, the delay computed
+// before any capping, and the source of the chosen delay.
+// When respectRetryAfter or retryAfterMaxWait is set, a Retry-After header on 429 and 503
+// responses is honored when it asks for a longer wait than the strategy, up to the
+// transport's maximum delay, or up to retryAfterMaxWait when that is set: longer waits
+// never get here, as they stop the retries.
+func (r *retryTransport) retryDelay(strategy RetryStrategy, attempt int, resp *http.Response) (delay, computed time.Duration, source string) {
+	delay = strategy(attempt)
+	computed = delay
+	source = delaySourceStrategy
+
+	if r.strategyCapping != nil {
+		if uncapped, capped := r.strategyCapping(attempt); capped {
+			computed = uncapped
+			source = delaySourceCapped
+		}
+	}
+
+	if r.respectRetryAfter || r.retryAfterMaxWait > 0 {
+		if retryAfter, ok := retryAfterDelay(resp, time.Now()); ok && retryAfter > delay {
+			delay = retryAfter
+			computed = retryAfter
+			source = delaySourceRetryAfter
+
+			if r.retryAfterMaxWait == 0 && r.maxDelay > 0 && delay > r.maxDelay {
+				delay = r.maxDelay
+				source = delaySourceCapped
+			}
+		}
+	}
+
+	// The absolute maximum is the final clamp, applied after strategy, jitter and Retry-After
+	if r.absoluteMaxDelay > 0 && delay > r.absoluteMaxDelay {
+		delay = r.absoluteMaxDelay
+		source = delaySourceCapped
+	}
+
+	return delay, computed, source
+}
+
+// retryAfterDelay parses the Retry-After header of 429 and 503 responses.
+// Both delay-seconds and HTTP-date formats are supported (RFC 9110, section 10.2.3).
+func retryAfterDelay(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	delay := date.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+
+	return delay, true
+}
+
+// requestMaxRetriesKey is the context key of the retry limit set with WithRequestMaxRetries.
+type requestMaxRetriesKey struct{}
+
+// WithRequestMaxRetries returns a copy of ctx that sets the maximum number of retries of
+// requests sent with it, e.g. 0 to never retry a non-idempotent call on a client that
+// otherwise retries, without creating a second client. The limit takes precedence over
+// the client configuration, including WithMaxRetries and WithMaxRetriesForStatus; other
+// retry rules, such as the retry budget, still apply. Negative values are ignored.
+func WithRequestMaxRetries(ctx context.Context, n int) context.Context {
+	if n < 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, requestMaxRetriesKey{}, n)
+}
+
+// maxRetriesFor returns the retry limit that applies to the outcome of an attempt.
+// A limit set on the request context with WithRequestMaxRetries takes precedence.
+// Otherwise, responses whose status code is listed in maxRetriesForStatus use that
+// limit, and everything else (including transport errors) uses MaxRetries.
+func (r *retryTransport) maxRetriesFor(req *http.Request, resp *http.Response, err error) int {
+	if maxRetries, ok := req.Context().Value(requestMaxRetriesKey{}).(int); ok {
+		return maxRetries
+	}
+
+	if err == nil && resp != nil {
+		if maxRetries, ok := r.maxRetriesForStatus[resp.StatusCode]; ok {
+			return maxRetries
+		}
+	}
+
+	return r.MaxRetries
+}
+
+// CloseIdleConnections closes the idle connections of the underlying transport if it
+// supports it, so that http.Client.CloseIdleConnections reaches it.
+func (r *retryTransport) CloseIdleConnections() {
+	if closer, ok := r.Transport.(idleConnectionsCloser); ok {
+		closer.CloseIdleConnections()
+	}
 }
 
 // RoundTrip executes an HTTP request with retry logic
 func (r *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.bodyReadTimeout <= 0 {
+		return r.boundedRoundTrip(req)
+	}
+
+	// A stalled body read is aborted through the request context, so the context must
+	// stay alive while the body is read; it is released on Close
+	ctx, cancel := context.WithCancelCause(req.Context())
+
+	resp, err := r.boundedRoundTrip(req.WithContext(ctx))
+	if err != nil || resp == nil {
+		cancel(nil)
+
+		return resp, err
+	}
+
+	resp.Body = newReadTimeoutBody(ctx, cancel, resp.Body, r.bodyReadTimeout)
+
+	return resp, nil
+}
+
+// boundedRoundTrip executes an HTTP request with retry logic, bounded by the maximum
+// response time if set.
+func (r *retryTransport) boundedRoundTrip(req *http.Request) (*http.Response, error) {
+	if r.maxResponseTime <= 0 {
+		return r.roundTrip(req)
+	}
+
+	// Bound all attempts and retry delays by the maximum response time. The timeout is
+	// applied through the request context, so in-flight attempts are aborted when it fires.
+	start := time.Now()
+	ctx, cancel := context.WithTimeoutCause(req.Context(), r.maxResponseTime, ErrResponseTooSlow)
+
+	resp, err := r.roundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+
+		if errors.Is(context.Cause(ctx), ErrResponseTooSlow) {
+			elapsed := time.Since(start)
+			if r.logger != nil {
+				r.logger.Error("HTTP request exceeded maximum response time",
+					"max_response_time", r.maxResponseTime,
+					"elapsed", elapsed,
+					"url", req.URL.String(),
+					"method", req.Method,
+				)
+			}
+
+			return nil, fmt.Errorf("%w: no response within %v (elapsed %v): %w", ErrResponseTooSlow, r.maxResponseTime, elapsed.Round(time.Millisecond), err)
+		}
+
+		return nil, err
+	}
+
+	// The context must stay alive while the body is read; release it on Close
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, ctx: ctx, cancel: cancel}
+
+	return resp, nil
+}
+
+// bufferRequestBody reads the body of req into memory if it is at most limit bytes and
+// returns a copy of req with a replayable body and GetBody. Larger bodies are not
+// buffered: the copy streams the bytes already read followed by the rest of the body.
+// It reports whether the body was buffered.
+func bufferRequestBody(req *http.Request, limit int) (*http.Request, bool, error) {
+	data, err := io.ReadAll(io.LimitReader(req.Body, int64(limit)+1))
+	if err != nil {
+		_ = req.Body.Close()
+		return nil, false, fmt.Errorf("failed to buffer request body for retry: %w", err)
+	}
+
+	buffered := req.WithContext(req.Context())
+
+	if len(data) > limit {
+		buffered.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), req.Body), req.Body}
+
+		return buffered, false, nil
+	}
+
+	_ = req.Body.Close()
+
+	buffered.Body = io.NopCloser(bytes.NewReader(data))
+	buffered.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	return buffered, true, nil
+}
+
+// cancelOnCloseBody is a response body that cancels the request context when closed.
+// Reads that fail because the maximum response time elapsed report ErrResponseTooSlow.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		if cause := context.Cause(b.ctx); errors.Is(cause, ErrResponseTooSlow) || errors.Is(cause, ErrAttemptTimeout) {
+			err = fmt.Errorf("%w: %w", cause, err)
+		}
+	}
+
+	return n, err
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+
+	return b.ReadCloser.Close()
+}
+
+// readTimeoutBody is a response body whose reads fail with ErrBodyReadTimeout when no data
+// arrives within the timeout. The timer only runs while a read is in progress, so a caller
+// that is slow to consume the body is never mistaken for a stalled server.
+type readTimeoutBody struct {
+	io.ReadCloser
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+// newReadTimeoutBody wraps body to cancel ctx with ErrBodyReadTimeout when a read waits
+// longer than timeout.
+func newReadTimeoutBody(ctx context.Context, cancel context.CancelCauseFunc, body io.ReadCloser, timeout time.Duration) *readTimeoutBody {
+	timer := time.AfterFunc(timeout, func() { cancel(ErrBodyReadTimeout) })
+	timer.Stop()
+
+	return &readTimeoutBody{ReadCloser: body, ctx: ctx, cancel: cancel, timeout: timeout, timer: timer}
+}
+
+func (b *readTimeoutBody) Read(p []byte) (int, error) {
+	b.timer.Reset(b.timeout)
+	n, err := b.ReadCloser.Read(p)
+	b.timer.Stop()
+
+	if err != nil && err != io.EOF && errors.Is(context.Cause(b.ctx), ErrBodyReadTimeout) {
+		err = fmt.Errorf("%w: no data within %v: %w", ErrBodyReadTimeout, b.timeout, err)
+	}
+
+	return n, err
+}
+
+func (b *readTimeoutBody) Close() error {
+	b.timer.Stop()
+	defer b.cancel(nil)
+
+	return b.ReadCloser.Close()
+}
+
+// withAttemptTimeout returns req with a context bounded by the per-attempt timeout and
+// the function releasing it. Without a per-attempt timeout, req is returned as is.
+func (r *retryTransport) withAttemptTimeout(req *http.Request) (*http.Request, context.CancelFunc) {
+	if r.perAttemptTimeout <= 0 {
+		return req, func() {}
+	}
+
+	ctx, cancel := context.WithTimeoutCause(req.Context(), r.perAttemptTimeout, ErrAttemptTimeout)
+
+	return req.WithContext(ctx), cancel
+}
+
+// releaseOnClose keeps the context of the attempt that returned resp alive until its
+// body is closed. Without a per-attempt timeout, resp is returned as is.
+func (r *retryTransport) releaseOnClose(resp *http.Response, attemptReq *http.Request, cancel context.CancelFunc) *http.Response {
+	if r.perAttemptTimeout <= 0 {
+		return resp
+	}
+
+	if resp == nil {
+		cancel()
+
+		return nil
+	}
+
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, ctx: attemptReq.Context(), cancel: cancel}
+
+	return resp
+}
+
+// withAttemptsHeader sets the attempts header of resp to the number of attempts made for
+// the request, if the header is configured and a response was received.
+func (r *retryTransport) withAttemptsHeader(resp *http.Response, attempts int) *http.Response {
+	if r.attemptsHeader == "" || resp == nil {
+		return resp
+	}
+
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
+	}
+	resp.Header.Set(r.attemptsHeader, strconv.Itoa(attempts))
+
+	return resp
+}
+
+// roundTrip runs the attempts of a request until it succeeds or retrying stops.
+func (r *retryTransport) roundTrip(req *http.Request) (*http.Response, error) {
 	var resp *http.Response
 	var err error
 
@@ -90,23 +760,103 @@ func (r *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		retryStrategy = ExponentialBackoff(500*time.Millisecond, 10*time.Second) // Default strategy
 	}
 
-	for attempt := 0; attempt <= r.MaxRetries; attempt++ {
-		// Clone the request body if it exists and is GetBody is defined
-		// This allows the body to be read multiple times on retries
+	if r.budget != nil {
+		r.budget.recordRequest(time.Now())
+	}
+
+	req = r.withDefaultHeaders(req)
+
+	// A body without GetBody is consumed by the first attempt, so a retry would send it
+	// empty. Such bodies are buffered so that they can be retried if enabled, unless they
+	// are too large; otherwise the request is sent only once
+	sendOnce := false
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		sendOnce = true
+
+		if r.maxRetryBodyBytes > 0 {
+			var buffered bool
+			req, buffered, err = bufferRequestBody(req, r.maxRetryBodyBytes)
+			if err != nil {
+				return nil, err
+			}
+			sendOnce = !buffered
+
+			if !buffered && r.logger != nil {
+				r.logger.Debug("Request body too large to buffer for retries, sending once",
+					"max_retry_body_bytes", r.maxRetryBodyBytes,
+					"url", req.URL.String(),
+					"method", req.Method,
+				)
+			}
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		countAttempt(req.Context())
+
+		// Send a clone with a fresh body from GetBody on every attempt, so that the body
+		// can be read again on retries without mutating the caller's request, which can
+		// then be reused
+		sendReq := req
 		if req.Body != nil && req.GetBody != nil {
 			bodyClone, err := req.GetBody()
 			if err != nil {
 				return nil, fmt.Errorf("failed to get request body for retry: %w", err)
 			}
 
-			req.Body = bodyClone
+			sendReq = req.Clone(req.Context())
+			sendReq.Body = bodyClone
+		}
+
+		// Track whether the attempt reuses a pooled connection, where EOF means the server
+		// closed it just as it was reused, and when connections with a lifetime are in use
+		var reusedConn atomic.Bool
+		if !r.noConnectionResetRetry || r.connLifetime {
+			trace := &httptrace.ClientTrace{
+				GotConn: func(info httptrace.GotConnInfo) { reusedConn.Store(info.Reused) },
+			}
+			if r.connLifetime {
+				connLifetimeTrace(trace)
+			}
+
+			sendReq = sendReq.WithContext(httptrace.WithClientTrace(sendReq.Context(), trace))
 		}
 
-		resp, err = transport.RoundTrip(req)
+		attemptReq, cancelAttempt := r.withAttemptTimeout(sendReq)
+		resp, err = transport.RoundTrip(r.withDeadlineHeader(attemptReq))
+
+		// An attempt that ran out of its own time is retried like any transient error,
+		// as long as the request itself is not done
+		attemptTimedOut := err != nil && req.Context().Err() == nil &&
+			errors.Is(context.Cause(attemptReq.Context()), ErrAttemptTimeout)
+		if attemptTimedOut {
+			err = fmt.Errorf("%w after %v: %w", ErrAttemptTimeout, r.perAttemptTimeout, err)
+		}
 
 		// Success conditions: no error and status code below 500 (excluding 429 Too Many Requests)
 		if err == nil && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
-			return resp, nil
+			return r.releaseOnClose(r.withAttemptsHeader(resp, attempt+1), attemptReq, cancelAttempt), nil
+		}
+
+		// Deterministic failures, e.g. 501 Not Implemented, are returned without retrying
+		if err == nil && r.nonRetryableStatus[resp.StatusCode] {
+			if r.logger != nil {
+				r.logger.Debug("HTTP request returned non-retryable status",
+					"attempt", attempt+1,
+					"status_code", resp.StatusCode,
+					"url", req.URL.String(),
+					"method", req.Method,
+				)
+			}
+
+			return r.releaseOnClose(r.withAttemptsHeader(resp, attempt+1), attemptReq, cancelAttempt), nil
+		}
+
+		// Requests whose body cannot be replayed are returned as is rather than retried
+		if sendOnce {
+			r.logNotReplayable(req, resp, err, reusedConn.Load())
+
+			return r.releaseOnClose(r.withAttemptsHeader(resp, attempt+1), attemptReq, cancelAttempt), err
 		}
 
 		// If there was an error or a server-side error (5xx), prepare for retry
@@ -126,93 +876,179 @@ func (r *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 			}
 		}
 
+		cancelAttempt()
+
 		// Do not retry if the error is due to context cancellation or deadline exceeded.
 		// When http.Client.Timeout fires, it cancels the request context. Since this
 		// context is shared across all retry attempts, subsequent retries would fail
 		// immediately. Return the original error to avoid misleading "retry cancelled" messages.
-		if err != nil {
+		if err != nil && !attemptTimedOut {
 			if ctx := req.Context(); ctx != nil && ctx.Err() != nil {
 				return nil, err
 			}
-		}
 
-		// Check if we should retry
-		if attempt < r.MaxRetries {
-			delay := retryStrategy(attempt)
+			// Context errors are final even when they come from a context other than the
+			// request's, e.g. a deadline set by a wrapped transport, and even when a custom
+			// retryable error function would retry them: retrying cannot outlive a deadline.
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil, err
+			}
 
-			// Log retry attempt if logger is configured
-			if r.logger != nil {
-				if err != nil {
-					r.logger.Warn("HTTP request failed, retrying",
+			// Permanent errors, e.g. certificate verification failures, fail immediately
+			if !r.isRetryableError(err, reusedConn.Load()) {
+				if r.logger != nil {
+					r.logger.Debug("HTTP request failed with non-retryable error",
 						"attempt", attempt+1,
-						"max_retries", r.MaxRetries,
-						"delay", delay,
 						"error", err,
 						"url", req.URL.String(),
 						"method", req.Method,
 					)
-				} else if resp != nil {
-					r.logger.Warn("HTTP request returned server error, retrying",
-						"attempt", attempt+1,
-						"max_retries", r.MaxRetries,
-						"delay", delay,
-						"status_code", resp.StatusCode,
-						"url", req.URL.String(),
-						"method", req.Method,
-					)
 				}
-			}
 
-			// Respect context cancellation during retry delay
-			if ctx := req.Context(); ctx != nil {
-				timer := time.NewTimer(delay)
-				select {
-				case <-ctx.Done():
-					timer.Stop()
-					if err != nil {
-						return nil, fmt.Errorf("retry cancelled: %w", ctx.Err())
-					}
-					return nil, fmt.Errorf("retry cancelled: %w", ctx.Err())
-				case <-timer.C:
-				}
-			} else {
-				time.Sleep(delay)
+				return nil, err
 			}
-		} else {
+		}
+
+		// Check if we should retry
+		maxRetries := r.maxRetriesFor(req, resp, err)
+		if attempt >= maxRetries {
 			// Max retries reached, log and return the last error or a generic failure error
-			if r.logger != nil {
-				if err != nil {
-					r.logger.Error("All retry attempts failed",
-						"attempts", r.MaxRetries+1,
-						"error", err,
-						"url", req.URL.String(),
-						"method", req.Method,
-					)
-				} else if resp != nil {
-					r.logger.Error("All retry attempts failed",
-						"attempts", r.MaxRetries+1,
-						"status_code", resp.StatusCode,
-						"url", req.URL.String(),
-						"method", req.Method,
-					)
-				}
+			return nil, r.retriesExhausted(req, resp, err, attempt+1, ErrAllRetriesFailed)
+		}
+
+		// Give up rather than wait longer than allowed for the server to recover
+		if r.retryAfterMaxWait > 0 {
+			if retryAfter, ok := retryAfterDelay(resp, time.Now()); ok && retryAfter > r.retryAfterMaxWait {
+				reason := fmt.Errorf("%w (%v > %v)", ErrRetryAfterTooLong, retryAfter.Round(time.Second), r.retryAfterMaxWait)
+
+				return nil, r.retriesExhausted(req, resp, err, attempt+1, reason)
 			}
+		}
 
+		// Do not spend another attempt when the retry budget is exhausted
+		if r.budget != nil && !r.budget.allowRetry(time.Now()) {
+			return nil, r.retriesExhausted(req, resp, err, attempt+1, ErrRetryBudgetExhausted)
+		}
+
+		delay, computedDelay, delaySource := r.retryDelay(retryStrategy, attempt, resp)
+
+		// Log retry attempt if logger is configured
+		if r.logger != nil {
 			if err != nil {
-				return nil, fmt.Errorf("all retries failed; last error: %w", err)
+				r.logger.Warn("HTTP request failed, retrying",
+					"attempt", attempt+1,
+					"max_retries", maxRetries,
+					"delay", delay,
+					"delay_source", delaySource,
+					"computed_delay", computedDelay,
+					"error", err,
+					"url", req.URL.String(),
+					"method", req.Method,
+				)
+			} else if resp != nil {
+				r.logger.Warn("HTTP request returned server error, retrying",
+					"attempt", attempt+1,
+					"max_retries", maxRetries,
+					"delay", delay,
+					"delay_source", delaySource,
+					"computed_delay", computedDelay,
+					"status_code", resp.StatusCode,
+					"url", req.URL.String(),
+					"method", req.Method,
+				)
 			}
+		}
 
-			// If the last attempt resulted in a 5xx response without a transport error
-			if resp != nil {
-				// Return a more specific error including the status code
-				return nil, fmt.Errorf("%w: last attempt failed with status %d", ErrAllRetriesFailed, resp.StatusCode)
+		// Respect context cancellation during retry delay
+		if ctx := req.Context(); ctx != nil {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				if err != nil {
+					return nil, fmt.Errorf("retry cancelled: %w", ctx.Err())
+				}
+				return nil, fmt.Errorf("retry cancelled: %w", ctx.Err())
+			case <-timer.C:
 			}
+		} else {
+			time.Sleep(delay)
+		}
+	}
+}
+
+// logNotReplayable warns that a failed request, which would otherwise be retried, is
+// returned as is because its body has no GetBody and cannot be sent again.
+func (r *retryTransport) logNotReplayable(req *http.Request, resp *http.Response, err error, reusedConn bool) {
+	if r.logger == nil || r.maxRetriesFor(req, resp, err) <= 0 {
+		return
+	}
+
+	if err != nil {
+		if req.Context().Err() != nil || !r.isRetryableError(err, reusedConn) {
+			return
+		}
 
-			return nil, ErrAllRetriesFailed
+		r.logger.Warn("HTTP request failed, not retrying because its body cannot be replayed (no GetBody)",
+			"error", err,
+			"url", req.URL.String(),
+			"method", req.Method,
+		)
+
+		return
+	}
+
+	r.logger.Warn("HTTP request returned server error, not retrying because its body cannot be replayed (no GetBody)",
+		"status_code", resp.StatusCode,
+		"url", req.URL.String(),
+		"method", req.Method,
+	)
+}
+
+// retriesExhausted logs the final failure of a request and returns the error to report.
+// reason is ErrAllRetriesFailed when the retry limit was reached, or a more specific
+// error when retrying was stopped early.
+func (r *retryTransport) retriesExhausted(req *http.Request, resp *http.Response, err error, attempts int, reason error) error {
+	if r.logger != nil {
+		msg := "All retry attempts failed"
+		if !errors.Is(reason, ErrAllRetriesFailed) {
+			msg = "Retrying stopped early"
+		}
+
+		if err != nil {
+			r.logger.Error(msg,
+				"attempts", attempts,
+				"reason", reason,
+				"error", err,
+				"url", req.URL.String(),
+				"method", req.Method,
+			)
+		} else if resp != nil {
+			r.logger.Error(msg,
+				"attempts", attempts,
+				"reason", reason,
+				"status_code", resp.StatusCode,
+				"url", req.URL.String(),
+				"method", req.Method,
+			)
 		}
 	}
 
-	return nil, ErrAllRetriesFailed
+	if err != nil {
+		if errors.Is(reason, ErrAllRetriesFailed) {
+			return fmt.Errorf("all retries failed; last error: %w", err)
+		}
+
+		return fmt.Errorf("%w; last error: %w", reason, err)
+	}
+
+	// If the last attempt resulted in a 5xx response without a transport error
+	if resp != nil {
+		// Return a more specific error including the status code
+		return fmt.Errorf("%w: last attempt failed with status %d", reason, resp.StatusCode)
+	}
+
+	return reason
 }
 
 // RetryClientOption is a function type for configuring the retry HTTP client.
@@ -225,6 +1061,31 @@ type retryClientConfig struct {
 	baseTransport http.RoundTripper
 	proxyURL      string // Proxy URL (e.g., "http://proxy.example.com:8080")
 	logger        *slog.Logger
+
+	proxyConnectHeader http.Header
+	minTLSVersion      uint16
+	dnsCacheTTL        time.Duration
+	maxHeaderBytes     int64
+	localAddr          net.Addr
+	unixSocket         string
+
+	maxRetriesForStatus     map[int]int
+	nonRetryableStatus      map[int]bool
+	deadlineHeader          string
+	attemptsHeader          string
+	defaultHeaders          http.Header
+	retryBudgetRatio        float64
+	retryBudgetMinPerSecond int
+	retryableError          func(error) bool
+	noConnectionResetRetry  bool
+	maxResponseTime         time.Duration
+	absoluteMaxDelay        time.Duration
+	retryAfterMaxWait       time.Duration
+	maxDelay                time.Duration
+	respectRetryAfter       bool
+	perAttemptTimeout       time.Duration
+	bodyReadTimeout         time.Duration
+	maxRetryBodyBytes       int
 }
 
 // WithMaxRetriesRetry sets the maximum number of retry attempts for the retry client.
@@ -234,6 +1095,152 @@ func WithMaxRetriesRetry(maxRetries int) RetryClientOption {
 	}
 }
 
+// WithMaxRetriesForStatusRetry sets per-status-code retry limits for the retry client.
+// Responses whose status code is present in the map use the mapped limit instead of
+// the global maximum; unlisted status codes and transport errors use the global maximum.
+// Negative limits are ignored.
+func WithMaxRetriesForStatusRetry(maxRetriesForStatus map[int]int) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.maxRetriesForStatus = copyMaxRetriesForStatus(maxRetriesForStatus)
+	}
+}
+
+// WithNonRetryableStatusCodesRetry sets status codes that the retry client never retries,
+// even though they are 5xx or 429, e.g. 501 Not Implemented. Responses with these status
+// codes are returned as is. Call it without arguments to clear the list.
+func WithNonRetryableStatusCodesRetry(codes ...int) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.nonRetryableStatus = newStatusCodeSet(codes)
+	}
+}
+
+// WithDeadlinePropagationHeaderRetry sets the header used by the retry client to propagate
+// the request context deadline to the server, e.g. "X-Request-Timeout" or "grpc-timeout".
+// See ClientBuilder.WithDeadlinePropagationHeader for the header format.
+func WithDeadlinePropagationHeaderRetry(name string) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.deadlineHeader = name
+	}
+}
+
+// WithAttemptsHeaderRetry sets a response header that the retry client sets to the number
+// of attempts made for the request, e.g. "X-Httpx-Attempts".
+// See ClientBuilder.WithAttemptsHeader for details.
+func WithAttemptsHeaderRetry(name string) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.attemptsHeader = name
+	}
+}
+
+// WithDefaultHeadersRetry sets headers that the retry client adds to every request that
+// does not set them itself, e.g. a User-Agent or an API version header, so they need not be
+// set per request. Header names are canonicalized, and calling it again adds to the
+// existing headers.
+func WithDefaultHeadersRetry(headers map[string]string) RetryClientOption {
+	return func(c *retryClientConfig) {
+		if c.defaultHeaders == nil {
+			c.defaultHeaders = make(http.Header, len(headers))
+		}
+
+		for key, value := range headers {
+			c.defaultHeaders.Set(key, value)
+		}
+	}
+}
+
+// WithRetryBudgetRetry limits the retries of the retry client across all of its requests.
+// See ClientBuilder.WithRetryBudget for how ratio and minPerSecond are applied.
+func WithRetryBudgetRetry(ratio float64, minPerSecond int) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.retryBudgetRatio = ratio
+		c.retryBudgetMinPerSecond = minPerSecond
+	}
+}
+
+// WithRetryableErrorFuncRetry sets the function deciding whether a transport error is retried
+// by the retry client. See ClientBuilder.WithRetryableErrorFunc for details.
+func WithRetryableErrorFuncRetry(fn func(error) bool) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.retryableError = fn
+	}
+}
+
+// WithRetryOnConnectionResetRetry sets whether the retry client always retries connection
+// resets. See ClientBuilder.WithRetryOnConnectionReset for details.
+func WithRetryOnConnectionResetRetry(enabled bool) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.noConnectionResetRetry = !enabled
+	}
+}
+
+// WithPerAttemptTimeoutRetry bounds each attempt of a request of the retry client, so that
+// every retry gets the full timeout. See ClientBuilder.WithPerAttemptTimeout for details.
+func WithPerAttemptTimeoutRetry(timeout time.Duration) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.perAttemptTimeout = timeout
+	}
+}
+
+// WithBodyReadTimeoutRetry bounds how long each read of a response body of the retry
+// client waits for data. See ClientBuilder.WithBodyReadTimeout for details.
+func WithBodyReadTimeoutRetry(timeout time.Duration) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.bodyReadTimeout = timeout
+	}
+}
+
+// WithAbsoluteMaxDelayRetry sets a hard ceiling on every retry delay of the retry client.
+// See ClientBuilder.WithAbsoluteMaxDelay for details.
+func WithAbsoluteMaxDelayRetry(d time.Duration) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.absoluteMaxDelay = d
+	}
+}
+
+// WithRespectRetryAfterRetry sets whether the retry client waits as long as the Retry-After
+// header of 429 and 503 responses asks. See ClientBuilder.WithRespectRetryAfter for details.
+func WithRespectRetryAfterRetry(enabled bool) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.respectRetryAfter = enabled
+	}
+}
+
+// WithRetryMaxDelayRetry sets the longest Retry-After delay the retry client waits for when
+// WithRespectRetryAfterRetry is enabled; longer ones are capped. A limit set with
+// WithRetryAfterMaxWaitRetry replaces it. It does not apply to the
+// retry strategy, which carries its own maximum. Pass 0 to disable the cap.
+// The default is DefaultMaxDelay.
+func WithRetryMaxDelayRetry(maxDelay time.Duration) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.maxDelay = maxDelay
+	}
+}
+
+// WithRetryAfterMaxWaitRetry sets the longest Retry-After delay the retry client honors.
+// See ClientBuilder.WithRetryAfterMaxWait for details.
+func WithRetryAfterMaxWaitRetry(d time.Duration) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.retryAfterMaxWait = d
+	}
+}
+
+// WithMaxResponseTimeRetry bounds the total time of a request of the retry client, including
+// all retries and retry delays. See ClientBuilder.WithMaxResponseTime for details.
+func WithMaxResponseTimeRetry(maxResponseTime time.Duration) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.maxResponseTime = maxResponseTime
+	}
+}
+
+// WithMaxRetryBodyBytesRetry buffers request bodies without GetBody of at most n bytes so
+// that the retry client can retry them; larger bodies are sent once.
+// See ClientBuilder.WithMaxRetryBodyBytes for details.
+func WithMaxRetryBodyBytesRetry(n int) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.maxRetryBodyBytes = n
+	}
+}
+
 // WithRetryStrategyRetry sets the retry strategy for the retry client.
 func WithRetryStrategyRetry(strategy RetryStrategy) RetryClientOption {
 	return func(c *retryClientConfig) {
@@ -266,13 +1273,70 @@ func WithProxyRetry(proxyURL string) RetryClientOption {
 	}
 }
 
+// WithProxyConnectHeaderRetry sets headers sent on the CONNECT request when the retry
+// client tunnels HTTPS through an HTTP proxy. The header is applied to a clone of the
+// base transport when it is an *http.Transport; headers with CR or LF characters in a
+// key or value are ignored with a warning.
+func WithProxyConnectHeaderRetry(header http.Header) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.proxyConnectHeader = header.Clone()
+	}
+}
+
+// WithMinTLSVersionRetry sets the minimum TLS version accepted by the retry client,
+// e.g. tls.VersionTLS12. The version is applied to a clone of the base transport when
+// it is an *http.Transport; unknown versions are ignored with a warning.
+func WithMinTLSVersionRetry(version uint16) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.minTLSVersion = version
+	}
+}
+
+// WithDNSCacheRetry caches the addresses resolved for each host for the given TTL.
+// The cache wraps the DialContext of a clone of the base transport when it is an
+// *http.Transport. See ClientBuilder.WithDNSCache for details.
+func WithDNSCacheRetry(ttl time.Duration) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.dnsCacheTTL = ttl
+	}
+}
+
+// WithMaxHeaderBytesRetry limits the size of the response headers accepted by the retry client.
+// It is set on a clone of the base transport when it is an *http.Transport.
+// See ClientBuilder.WithMaxHeaderBytes for details.
+func WithMaxHeaderBytesRetry(maxHeaderBytes int64) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.maxHeaderBytes = maxHeaderBytes
+	}
+}
+
+// WithLocalAddrRetry binds the outgoing connections of the retry client to the given local
+// address. It replaces the dialer of a clone of the base transport when it is an
+// *http.Transport. See ClientBuilder.WithLocalAddr for details.
+func WithLocalAddrRetry(addr net.Addr) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.localAddr = addr
+	}
+}
+
+// WithUnixSocketRetry sends all requests of the retry client over the unix domain socket
+// at the given path. It replaces the dialer of a clone of the base transport when it is
+// an *http.Transport. See ClientBuilder.WithUnixSocket for details.
+func WithUnixSocketRetry(path string) RetryClientOption {
+	return func(c *retryClientConfig) {
+		c.unixSocket = path
+	}
+}
+
 // NewHTTPRetryClient creates a new http.Client configured with the retry transport.
 // Use the provided options to customize the retry behavior.
 // By default, it uses 3 retries with exponential backoff strategy and no logging.
+// Retry-After headers are ignored unless enabled with WithRespectRetryAfterRetry.
 func NewHTTPRetryClient(options ...RetryClientOption) *http.Client {
 	config := &retryClientConfig{
 		maxRetries:    DefaultMaxRetries,
 		strategy:      ExponentialBackoff(DefaultBaseDelay, DefaultMaxDelay),
+		maxDelay:      DefaultMaxDelay,
 		baseTransport: nil,
 		logger:        nil,
 	}
@@ -309,6 +1373,95 @@ func NewHTTPRetryClient(options ...RetryClientOption) *http.Client {
 		}
 	}
 
+	// Configure proxy CONNECT headers if provided
+	if len(config.proxyConnectHeader) > 0 {
+		if err := validateHeaderFields(config.proxyConnectHeader); err != nil {
+			if config.logger != nil {
+				config.logger.Warn("Invalid proxy CONNECT header, proceeding without it", "error", err)
+			}
+		} else if transport, ok := config.baseTransport.(*http.Transport); ok {
+			// Clone the transport to avoid mutating the original
+			clonedTransport := transport.Clone()
+			clonedTransport.ProxyConnectHeader = config.proxyConnectHeader.Clone()
+			config.baseTransport = clonedTransport
+		} else if config.logger != nil {
+			config.logger.Warn("Custom transport provided; proxy CONNECT header ignored. Configure it on your custom transport directly.")
+		}
+	}
+
+	// Configure the minimum TLS version if provided
+	if config.minTLSVersion != 0 {
+		if !isValidTLSVersion(config.minTLSVersion) {
+			if config.logger != nil {
+				config.logger.Warn("Invalid minimum TLS version, using Go default", "invalidValue", config.minTLSVersion)
+			}
+		} else if transport, ok := config.baseTransport.(*http.Transport); ok {
+			// Clone the transport and its TLS configuration to avoid mutating the originals
+			clonedTransport := transport.Clone()
+			ensureTLSClientConfig(clonedTransport).MinVersion = config.minTLSVersion
+			config.baseTransport = clonedTransport
+		} else if config.logger != nil {
+			config.logger.Warn("Custom transport provided; minimum TLS version ignored. Configure it on your custom transport directly.")
+		}
+	}
+
+	// Limit the size of response headers if provided
+	if config.maxHeaderBytes > 0 {
+		if transport, ok := config.baseTransport.(*http.Transport); ok {
+			// Clone the transport to avoid mutating the original
+			clonedTransport := transport.Clone()
+			clonedTransport.MaxResponseHeaderBytes = config.maxHeaderBytes
+			config.baseTransport = clonedTransport
+		} else if config.logger != nil {
+			config.logger.Warn("Custom transport provided; max header bytes ignored. Configure it on your custom transport directly.")
+		}
+	}
+
+	// Bind outgoing connections to the local address if provided
+	if config.localAddr != nil {
+		if transport, ok := config.baseTransport.(*http.Transport); ok {
+			// Clone the transport to avoid mutating the original
+			clonedTransport := transport.Clone()
+
+			dialer := newDefaultDialer()
+			dialer.LocalAddr = config.localAddr
+			clonedTransport.DialContext = dialer.DialContext
+			config.baseTransport = clonedTransport
+		} else if config.logger != nil {
+			config.logger.Warn("Custom transport provided; local address ignored. Configure it on your custom transport directly.")
+		}
+	}
+
+	// Configure the DNS cache if provided
+	if config.dnsCacheTTL > 0 {
+		if transport, ok := config.baseTransport.(*http.Transport); ok {
+			// Clone the transport to avoid mutating the original
+			clonedTransport := transport.Clone()
+
+			dial := dialContextFunc(clonedTransport.DialContext)
+			if dial == nil {
+				dial = newDefaultDialer().DialContext
+			}
+
+			clonedTransport.DialContext = newDNSCache(config.dnsCacheTTL).dialContext(dial)
+			config.baseTransport = clonedTransport
+		} else if config.logger != nil {
+			config.logger.Warn("Custom transport provided; DNS cache ignored. Configure it on your custom transport directly.")
+		}
+	}
+
+	// Dial the unix socket if provided, replacing any other dialer
+	if config.unixSocket != "" {
+		if transport, ok := config.baseTransport.(*http.Transport); ok {
+			// Clone the transport to avoid mutating the original
+			clonedTransport := transport.Clone()
+			clonedTransport.DialContext = unixSocketDialContext(config.unixSocket)
+			config.baseTransport = clonedTransport
+		} else if config.logger != nil {
+			config.logger.Warn("Custom transport provided; unix socket ignored. Configure it on your custom transport directly.")
+		}
+	}
+
 	if config.strategy == nil {
 		config.strategy = ExponentialBackoff(DefaultBaseDelay, DefaultMaxDelay)
 	}
@@ -319,6 +1472,58 @@ func NewHTTPRetryClient(options ...RetryClientOption) *http.Client {
 			MaxRetries:    config.maxRetries,
 			RetryStrategy: config.strategy,
 			logger:        config.logger,
+
+			maxRetriesForStatus: config.maxRetriesForStatus,
+			nonRetryableStatus:  config.nonRetryableStatus,
+			maxDelay:            config.maxDelay,
+			respectRetryAfter:   config.respectRetryAfter,
+			deadlineHeader:      config.deadlineHeader,
+			attemptsHeader:      config.attemptsHeader,
+			defaultHeaders:      config.defaultHeaders,
+			budget:              newRetryBudget(config.retryBudgetRatio, config.retryBudgetMinPerSecond),
+			retryableError:      config.retryableError,
+			maxResponseTime:     config.maxResponseTime,
+			absoluteMaxDelay:    config.absoluteMaxDelay,
+			retryAfterMaxWait:   config.retryAfterMaxWait,
+			maxRetryBodyBytes:   config.maxRetryBodyBytes,
+			perAttemptTimeout:   config.perAttemptTimeout,
+			bodyReadTimeout:     config.bodyReadTimeout,
+
+			noConnectionResetRetry: config.noConnectionResetRetry,
 		},
 	}
 }
+
+// copyMaxRetriesForStatus returns a copy of the given per-status retry limits,
+// dropping negative limits. It returns nil when there is nothing to keep.
+func copyMaxRetriesForStatus(maxRetriesForStatus map[int]int) map[int]int {
+	if len(maxRetriesForStatus) == 0 {
+		return nil
+	}
+
+	limits := make(map[int]int, len(maxRetriesForStatus))
+	for statusCode, maxRetries := range maxRetriesForStatus {
+		if maxRetries < 0 {
+			continue
+		}
+
+		limits[statusCode] = maxRetries
+	}
+
+	return limits
+}
+
+// newStatusCodeSet returns the set of the given status codes.
+// It returns nil when there are none.
+func newStatusCodeSet(codes []int) map[int]bool {
+	if len(codes) == 0 {
+		return nil
+	}
+
+	set := make(map[int]bool, len(codes))
+	for _, code := range codes {
+		set[code] = true
+	}
+
+	return set
+}
@@ -1,15 +1,25 @@
 package httpx
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"math"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"strconv"
 	"strings"
 	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -88,6 +98,96 @@ func TestJitterBackoff(t *testing.T) {
 	}
 }
 
+func TestExponentialBackoffWithMultiplier(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       time.Duration
+		maxDelay   time.Duration
+		multiplier float64
+		expected   []time.Duration
+	}{
+		{
+			name:       "multiplier 1.5",
+			base:       time.Second,
+			maxDelay:   4 * time.Second,
+			multiplier: 1.5,
+			expected:   []time.Duration{time.Second, 1500 * time.Millisecond, 2250 * time.Millisecond, 3375 * time.Millisecond, 4 * time.Second, 4 * time.Second},
+		},
+		{
+			name:       "multiplier 3.0",
+			base:       100 * time.Millisecond,
+			maxDelay:   2 * time.Second,
+			multiplier: 3.0,
+			expected:   []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond, 2 * time.Second, 2 * time.Second},
+		},
+		{
+			name:       "invalid multiplier falls back to doubling",
+			base:       100 * time.Millisecond,
+			maxDelay:   time.Second,
+			multiplier: 0.5,
+			expected:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := ExponentialBackoffWithMultiplier(tt.base, tt.maxDelay, tt.multiplier)
+			for attempt, want := range tt.expected {
+				if got := strategy(attempt); got != want {
+					t.Errorf("Attempt %d: expected %v, got %v", attempt, want, got)
+				}
+			}
+
+			// Large attempts stay capped instead of overflowing
+			if got := strategy(1000); got != tt.maxDelay {
+				t.Errorf("Attempt 1000: expected %v, got %v", tt.maxDelay, got)
+			}
+		})
+	}
+}
+
+func TestWithRetryMultiplier_Options(t *testing.T) {
+	clients := map[string]*http.Client{
+		"client builder": NewClientBuilder().
+			WithRetryBaseDelay(time.Second).
+			WithRetryMaxDelay(30 * time.Second).
+			WithRetryMultiplier(3).
+			Build(),
+		"generic client": NewGenericClient[User](
+			WithRetryBaseDelay[User](time.Second),
+			WithRetryMaxDelay[User](30*time.Second),
+			WithRetryMultiplier[User](3),
+		).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			strategy := client.Transport.(*retryTransport).RetryStrategy
+			assertEqual(t, 3*time.Second, strategy(1))
+			assertEqual(t, 9*time.Second, strategy(2))
+		})
+	}
+
+	t.Run("jitter strategy", func(t *testing.T) {
+		client := NewClientBuilder().
+			WithRetryStrategy(JitterBackoffStrategy).
+			WithRetryBaseDelay(time.Second).
+			WithRetryMaxDelay(30 * time.Second).
+			WithRetryMultiplier(3).
+			Build()
+
+		delay := client.Transport.(*retryTransport).RetryStrategy(2)
+		if delay < 9*time.Second || delay >= 13500*time.Millisecond {
+			t.Errorf("Expected delay in [9s, 13.5s), got %v", delay)
+		}
+	})
+
+	t.Run("invalid multiplier uses default", func(t *testing.T) {
+		client := NewClientBuilder().WithRetryBaseDelay(time.Second).WithRetryMultiplier(0.5).Build()
+		assertEqual(t, 4*time.Second, client.Transport.(*retryTransport).RetryStrategy(2))
+	})
+}
+
 // --- Test retryTransport ---
 
 // mockRoundTripper allows mocking http.RoundTripper behavior.
@@ -954,8 +1054,8 @@ func TestRetryTransport_LargeTimeoutPreserved(t *testing.T) {
 		Build()
 
 	// Verify the timeout was preserved (not reset to default 5s)
-	if httpClient.Timeout != 120*time.Second {
-		t.Errorf("Expected timeout 120s, got %v (timeout was silently reset)", httpClient.Timeout)
+	if got := attemptTimeout(t, httpClient); got != 120*time.Second {
+		t.Errorf("Expected timeout 120s, got %v (timeout was silently reset)", got)
 	}
 
 	req, err := http.NewRequest("GET", server.URL, nil)
@@ -1153,3 +1253,1590 @@ func TestRetryTransport_ContextCancelledDuringRetryDelay(t *testing.T) {
 		t.Errorf("Expected context.Canceled error, got: %v", err)
 	}
 }
+
+func TestRetryTransport_MaxRetriesForStatus(t *testing.T) {
+	tests := []struct {
+		name             string
+		statusCode       int
+		expectedAttempts int32
+	}{
+		{name: "429 uses per-status limit", statusCode: http.StatusTooManyRequests, expectedAttempts: 6},
+		{name: "500 uses per-status limit", statusCode: http.StatusInternalServerError, expectedAttempts: 2},
+		{name: "503 falls back to MaxRetries", statusCode: http.StatusServiceUnavailable, expectedAttempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+
+			mockRT := &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					atomic.AddInt32(&attempts, 1)
+					return &http.Response{
+						StatusCode: tt.statusCode,
+						Body:       io.NopCloser(strings.NewReader("error")),
+						Header:     make(http.Header),
+					}, nil
+				},
+			}
+
+			retryRT := &retryTransport{
+				Transport:     mockRT,
+				MaxRetries:    2,
+				RetryStrategy: FixedDelay(1 * time.Millisecond),
+				maxRetriesForStatus: map[int]int{
+					http.StatusTooManyRequests:     5,
+					http.StatusInternalServerError: 1,
+				},
+			}
+
+			req := httptest.NewRequest("GET", "http://example.com", nil)
+			_, err := retryRT.RoundTrip(req)
+			if !errors.Is(err, ErrAllRetriesFailed) {
+				t.Fatalf("Expected ErrAllRetriesFailed, got %v", err)
+			}
+
+			if got := atomic.LoadInt32(&attempts); got != tt.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedAttempts, got)
+			}
+		})
+	}
+}
+
+func TestWithMaxRetriesForStatus_Options(t *testing.T) {
+	limits := map[int]int{http.StatusTooManyRequests: 10, http.StatusBadGateway: -1}
+
+	retryClient := NewHTTPRetryClient(WithMaxRetriesForStatusRetry(limits))
+	builderClient := NewClientBuilder().WithMaxRetriesForStatus(limits).Build()
+	genericClient := NewGenericClient[User](WithMaxRetriesForStatus[User](limits))
+
+	clients := map[string]*http.Client{
+		"retry client":   retryClient,
+		"client builder": builderClient,
+		"generic client": genericClient.httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			rt, ok := client.Transport.(*retryTransport)
+			if !ok {
+				t.Fatalf("Expected *retryTransport, got %T", client.Transport)
+			}
+
+			// Negative limits are dropped, valid ones are kept
+			expected := map[int]int{http.StatusTooManyRequests: 10}
+			assertEqual(t, expected, rt.maxRetriesForStatus)
+		})
+	}
+
+	// Mutating the caller's map after configuration must not affect the client
+	limits[http.StatusTooManyRequests] = 1
+	rt := retryClient.Transport.(*retryTransport)
+	assertEqual(t, 10, rt.maxRetriesForStatus[http.StatusTooManyRequests])
+}
+
+func TestRetryTransport_NonRetryableStatusCodes(t *testing.T) {
+	tests := []struct {
+		name             string
+		statusCode       int
+		expectedAttempts int32
+		expectErr        bool
+	}{
+		{name: "Denylisted 501 is returned immediately", statusCode: http.StatusNotImplemented, expectedAttempts: 1},
+		{name: "Other 5xx are still retried", statusCode: http.StatusBadGateway, expectedAttempts: 3, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+
+			retryRT := &retryTransport{
+				Transport: &mockRoundTripper{
+					roundTripFunc: func(req *http.Request) (*http.Response, error) {
+						atomic.AddInt32(&attempts, 1)
+						return &http.Response{
+							StatusCode: tt.statusCode,
+							Body:       io.NopCloser(strings.NewReader("not implemented")),
+							Header:     make(http.Header),
+						}, nil
+					},
+				},
+				MaxRetries:         2,
+				RetryStrategy:      FixedDelay(1 * time.Millisecond),
+				nonRetryableStatus: newStatusCodeSet([]int{http.StatusNotImplemented}),
+			}
+
+			resp, err := retryRT.RoundTrip(httptest.NewRequest("GET", "http://example.com", nil))
+			if tt.expectErr {
+				if !errors.Is(err, ErrAllRetriesFailed) {
+					t.Fatalf("Expected ErrAllRetriesFailed, got %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Expected the response to be returned, got %v", err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				assertEqual(t, "not implemented", string(body))
+			}
+
+			if got := atomic.LoadInt32(&attempts); got != tt.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedAttempts, got)
+			}
+		})
+	}
+}
+
+func TestWithNonRetryableStatusCodes_Options(t *testing.T) {
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithNonRetryableStatusCodesRetry(501, 505)),
+		"client builder": NewClientBuilder().WithNonRetryableStatusCodes(501, 505).Build(),
+		"generic client": NewGenericClient[User](WithNonRetryableStatusCodes[User](501, 505)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			rt, ok := client.Transport.(*retryTransport)
+			if !ok {
+				t.Fatalf("Expected *retryTransport, got %T", client.Transport)
+			}
+
+			assertEqual(t, map[int]bool{501: true, 505: true}, rt.nonRetryableStatus)
+		})
+	}
+
+	t.Run("No codes clear the list", func(t *testing.T) {
+		client := NewClientBuilder().WithNonRetryableStatusCodes(501).WithNonRetryableStatusCodes().Build()
+		assertEqual(t, 0, len(client.Transport.(*retryTransport).nonRetryableStatus))
+	})
+}
+
+func TestWithRequestMaxRetries(t *testing.T) {
+	tests := []struct {
+		name             string
+		maxRetries       int
+		expectedAttempts int32
+	}{
+		{name: "Zero disables retries", maxRetries: 0, expectedAttempts: 1},
+		{name: "Overrides client limit", maxRetries: 1, expectedAttempts: 2},
+		{name: "Overrides per-status limit", maxRetries: 4, expectedAttempts: 5},
+		{name: "Negative keeps client limit", maxRetries: -1, expectedAttempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+
+			retryRT := &retryTransport{
+				Transport: &mockRoundTripper{
+					roundTripFunc: func(req *http.Request) (*http.Response, error) {
+						atomic.AddInt32(&attempts, 1)
+						return &http.Response{
+							StatusCode: http.StatusServiceUnavailable,
+							Body:       io.NopCloser(strings.NewReader("unavailable")),
+							Header:     make(http.Header),
+						}, nil
+					},
+				},
+				MaxRetries:          5,
+				RetryStrategy:       FixedDelay(1 * time.Millisecond),
+				maxRetriesForStatus: map[int]int{http.StatusServiceUnavailable: 2},
+			}
+
+			req := httptest.NewRequest("POST", "http://example.com", nil)
+			req = req.WithContext(WithRequestMaxRetries(req.Context(), tt.maxRetries))
+
+			_, err := retryRT.RoundTrip(req)
+			if !errors.Is(err, ErrAllRetriesFailed) {
+				t.Fatalf("Expected ErrAllRetriesFailed, got %v", err)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedAttempts, got)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_RetryDelay(t *testing.T) {
+	newResponse := func(statusCode int, retryAfter string) *http.Response {
+		header := make(http.Header)
+		if retryAfter != "" {
+			header.Set("Retry-After", retryAfter)
+		}
+
+		return &http.Response{StatusCode: statusCode, Header: header}
+	}
+
+	tests := []struct {
+		name             string
+		resp             *http.Response
+		maxDelay         time.Duration
+		expectedDelay    time.Duration
+		expectedComputed time.Duration
+		expectedSource   string
+	}{
+		{
+			name:             "transport error uses strategy",
+			resp:             nil,
+			maxDelay:         10 * time.Second,
+			expectedDelay:    100 * time.Millisecond,
+			expectedComputed: 100 * time.Millisecond,
+			expectedSource:   delaySourceStrategy,
+		},
+		{
+			name:             "429 with Retry-After seconds",
+			resp:             newResponse(http.StatusTooManyRequests, "2"),
+			maxDelay:         10 * time.Second,
+			expectedDelay:    2 * time.Second,
+			expectedComputed: 2 * time.Second,
+			expectedSource:   delaySourceRetryAfter,
+		},
+		{
+			name:             "503 with Retry-After capped at max delay",
+			resp:             newResponse(http.StatusServiceUnavailable, "3600"),
+			maxDelay:         10 * time.Second,
+			expectedDelay:    10 * time.Second,
+			expectedComputed: time.Hour,
+			expectedSource:   delaySourceCapped,
+		},
+		{
+			name:             "Retry-After shorter than strategy is ignored",
+			resp:             newResponse(http.StatusTooManyRequests, "0"),
+			maxDelay:         10 * time.Second,
+			expectedDelay:    100 * time.Millisecond,
+			expectedComputed: 100 * time.Millisecond,
+			expectedSource:   delaySourceStrategy,
+		},
+		{
+			name:             "Retry-After ignored on 500",
+			resp:             newResponse(http.StatusInternalServerError, "5"),
+			maxDelay:         10 * time.Second,
+			expectedDelay:    100 * time.Millisecond,
+			expectedComputed: 100 * time.Millisecond,
+			expectedSource:   delaySourceStrategy,
+		},
+		{
+			name:             "invalid Retry-After uses strategy",
+			resp:             newResponse(http.StatusTooManyRequests, "soon"),
+			maxDelay:         10 * time.Second,
+			expectedDelay:    100 * time.Millisecond,
+			expectedComputed: 100 * time.Millisecond,
+			expectedSource:   delaySourceStrategy,
+		},
+	}
+
+	t.Run("Retry-After ignored by default", func(t *testing.T) {
+		rt := &retryTransport{maxDelay: 10 * time.Second}
+
+		delay, computed, source := rt.retryDelay(FixedDelay(100*time.Millisecond), 0, newResponse(http.StatusTooManyRequests, "2"))
+
+		assertEqual(t, 100*time.Millisecond, delay)
+		assertEqual(t, 100*time.Millisecond, computed)
+		assertEqual(t, delaySourceStrategy, source)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &retryTransport{maxDelay: tt.maxDelay, respectRetryAfter: true}
+
+			delay, computed, source := rt.retryDelay(FixedDelay(100*time.Millisecond), 0, tt.resp)
+
+			assertEqual(t, tt.expectedDelay, delay)
+			assertEqual(t, tt.expectedComputed, computed)
+			assertEqual(t, tt.expectedSource, source)
+		})
+	}
+}
+
+func TestRetryTransport_RetryDelayStrategyCapping(t *testing.T) {
+	tests := []struct {
+		name             string
+		builder          *ClientBuilder
+		attempt          int
+		expectedDelay    time.Duration
+		expectedComputed time.Duration
+		expectedSource   string
+	}{
+		{
+			name:             "Exponential below the maximum",
+			builder:          NewClientBuilder().WithRetryBaseDelay(time.Second).WithRetryMaxDelay(10 * time.Second),
+			attempt:          2,
+			expectedDelay:    4 * time.Second,
+			expectedComputed: 4 * time.Second,
+			expectedSource:   delaySourceStrategy,
+		},
+		{
+			name:             "Exponential capped at the maximum",
+			builder:          NewClientBuilder().WithRetryBaseDelay(time.Second).WithRetryMaxDelay(10 * time.Second),
+			attempt:          4,
+			expectedDelay:    10 * time.Second,
+			expectedComputed: 16 * time.Second,
+			expectedSource:   delaySourceCapped,
+		},
+		{
+			name:             "Multiplier capped at the maximum",
+			builder:          NewClientBuilder().WithRetryBaseDelay(time.Second).WithRetryMaxDelay(10 * time.Second).WithRetryMultiplier(3),
+			attempt:          3,
+			expectedDelay:    10 * time.Second,
+			expectedComputed: 27 * time.Second,
+			expectedSource:   delaySourceCapped,
+		},
+		{
+			name:             "Custom strategy is not reported as capped",
+			builder:          NewClientBuilder().WithRetryStrategyFunc(FixedDelay(time.Second)),
+			attempt:          10,
+			expectedDelay:    time.Second,
+			expectedComputed: time.Second,
+			expectedSource:   delaySourceStrategy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := tt.builder.Build().Transport.(*retryTransport)
+
+			delay, computed, source := rt.retryDelay(rt.RetryStrategy, tt.attempt, nil)
+
+			assertEqual(t, tt.expectedDelay, delay)
+			assertEqual(t, tt.expectedComputed, computed)
+			assertEqual(t, tt.expectedSource, source)
+		})
+	}
+
+	t.Run("Jitter is capped before it is added", func(t *testing.T) {
+		rt := NewClientBuilder().
+			WithRetryStrategy(JitterBackoffStrategy).
+			WithRetryBaseDelay(time.Second).
+			WithRetryMaxDelay(10 * time.Second).
+			Build().Transport.(*retryTransport)
+
+		delay, computed, source := rt.retryDelay(rt.RetryStrategy, 4, nil)
+
+		assertTrue(t, delay >= 10*time.Second && delay < 15*time.Second)
+		assertEqual(t, 16*time.Second, computed)
+		assertEqual(t, delaySourceCapped, source)
+	})
+}
+
+func TestWithRespectRetryAfter_Options(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	strategy := FixedDelay(time.Millisecond)
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithRespectRetryAfterRetry(true), WithRetryStrategyRetry(strategy)),
+		"client builder": NewClientBuilder().WithRespectRetryAfter(true).WithRetryStrategyFunc(strategy).Build(),
+		"generic client": NewGenericClient[User](WithRespectRetryAfter[User](true), WithRetryStrategyFunc[User](strategy)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			rt := client.Transport.(*retryTransport)
+			assertTrue(t, rt.respectRetryAfter)
+		})
+	}
+
+	t.Run("Waits for Retry-After", func(t *testing.T) {
+		calls.Store(0)
+		start := time.Now()
+
+		resp, err := clients["client builder"].Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Errorf("Expected to wait for Retry-After, took %v", elapsed)
+		}
+	})
+
+	t.Run("Ignored by default", func(t *testing.T) {
+		calls.Store(0)
+		start := time.Now()
+
+		resp, err := NewHTTPRetryClient(WithRetryStrategyRetry(strategy)).Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("Expected Retry-After to be ignored, took %v", elapsed)
+		}
+	})
+
+	t.Run("Retry client max delay", func(t *testing.T) {
+		rt := NewHTTPRetryClient(WithRetryMaxDelayRetry(time.Minute)).Transport.(*retryTransport)
+		assertEqual(t, time.Minute, rt.maxDelay)
+
+		rt = NewHTTPRetryClient().Transport.(*retryTransport)
+		assertEqual(t, DefaultMaxDelay, rt.maxDelay)
+	})
+}
+
+func TestRetryAfterDelay_HTTPDate(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	header := make(http.Header)
+	header.Set("Retry-After", now.Add(30*time.Second).Format(http.TimeFormat))
+
+	delay, ok := retryAfterDelay(&http.Response{StatusCode: http.StatusTooManyRequests, Header: header}, now)
+	if !ok {
+		t.Fatal("Expected HTTP-date Retry-After to be parsed")
+	}
+
+	assertEqual(t, 30*time.Second, delay)
+}
+
+func TestRetryTransport_DeadlinePropagationHeader(t *testing.T) {
+	var received []string
+
+	mockRT := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			received = append(received, req.Header.Get("X-Request-Timeout"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("OK")),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	retryRT := &retryTransport{
+		Transport:      mockRT,
+		MaxRetries:     1,
+		RetryStrategy:  FixedDelay(1 * time.Millisecond),
+		deadlineHeader: "X-Request-Timeout",
+	}
+
+	t.Run("Sets remaining time when the context has a deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		req := httptest.NewRequest(http.MethodGet, "http://example.com", nil).WithContext(ctx)
+		resp, err := retryRT.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+
+		remaining, err := strconv.Atoi(received[len(received)-1])
+		if err != nil {
+			t.Fatalf("Expected numeric header value, got %q", received[len(received)-1])
+		}
+
+		if remaining <= 0 || remaining > 2000 {
+			t.Errorf("Expected remaining time in (0, 2000]ms, got %d", remaining)
+		}
+
+		// The caller's request must not be modified
+		if req.Header.Get("X-Request-Timeout") != "" {
+			t.Error("Expected the original request headers to be untouched")
+		}
+	})
+
+	t.Run("Skipped without a deadline", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		resp, err := retryRT.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+
+		assertEqual(t, "", received[len(received)-1])
+	})
+}
+
+func TestFormatDeadlineHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		remaining time.Duration
+		expected  string
+	}{
+		{name: "milliseconds", header: "X-Request-Timeout", remaining: 1500 * time.Millisecond, expected: "1500"},
+		{name: "grpc milliseconds", header: "grpc-timeout", remaining: 1500 * time.Millisecond, expected: "1500m"},
+		{name: "grpc canonical name", header: "Grpc-Timeout", remaining: 2 * time.Second, expected: "2000m"},
+		{name: "grpc seconds for long deadlines", header: "grpc-timeout", remaining: 48 * time.Hour, expected: "172800S"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, tt.expected, formatDeadlineHeader(tt.header, tt.remaining))
+		})
+	}
+}
+
+func TestWithDeadlinePropagationHeader_Options(t *testing.T) {
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithDeadlinePropagationHeaderRetry("grpc-timeout")),
+		"client builder": NewClientBuilder().WithDeadlinePropagationHeader("grpc-timeout").Build(),
+		"generic client": NewGenericClient[User](WithDeadlinePropagationHeader[User]("grpc-timeout")).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			rt, ok := client.Transport.(*retryTransport)
+			if !ok {
+				t.Fatalf("Expected *retryTransport, got %T", client.Transport)
+			}
+
+			assertEqual(t, "grpc-timeout", rt.deadlineHeader)
+		})
+	}
+}
+
+func TestRetryBudget_AllowRetry(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	t.Run("ratio of requests", func(t *testing.T) {
+		budget := newRetryBudget(0.5, 0)
+		for i := 0; i < 4; i++ {
+			budget.recordRequest(now)
+		}
+
+		assertEqual(t, true, budget.allowRetry(now))
+		assertEqual(t, true, budget.allowRetry(now))
+		assertEqual(t, false, budget.allowRetry(now))
+	})
+
+	t.Run("minimum per second", func(t *testing.T) {
+		budget := newRetryBudget(0, 1)
+		for i := 0; i < 10; i++ {
+			assertEqual(t, true, budget.allowRetry(now))
+		}
+		assertEqual(t, false, budget.allowRetry(now))
+	})
+
+	t.Run("old buckets leave the window", func(t *testing.T) {
+		budget := newRetryBudget(1, 0)
+		budget.recordRequest(now)
+		assertEqual(t, true, budget.allowRetry(now))
+		assertEqual(t, false, budget.allowRetry(now))
+
+		later := now.Add(retryBudgetWindow)
+		assertEqual(t, false, budget.allowRetry(later))
+		budget.recordRequest(later)
+		assertEqual(t, true, budget.allowRetry(later))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		if newRetryBudget(-1, 10) != nil || newRetryBudget(0, 0) != nil {
+			t.Error("Expected no budget for a negative ratio or zero values")
+		}
+	})
+}
+
+func TestRetryTransport_RetryBudgetExhausted(t *testing.T) {
+	var attempts int32
+
+	mockRT := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&attempts, 1)
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       io.NopCloser(strings.NewReader("error")),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	retryRT := &retryTransport{
+		Transport:     mockRT,
+		MaxRetries:    3,
+		RetryStrategy: FixedDelay(1 * time.Millisecond),
+		budget:        newRetryBudget(0.5, 0),
+	}
+
+	// The first request may spend half a retry per request sent so far
+	req := httptest.NewRequest("GET", "http://example.com", nil)
+	_, err := retryRT.RoundTrip(req)
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("Expected ErrRetryBudgetExhausted, got %v", err)
+	}
+	assertEqual(t, int32(2), atomic.LoadInt32(&attempts))
+
+	expectedErrMsg := fmt.Sprintf("%s: last attempt failed with status %d", ErrRetryBudgetExhausted, http.StatusServiceUnavailable)
+	assertEqual(t, expectedErrMsg, err.Error())
+}
+
+func TestWithRetryBudget_Options(t *testing.T) {
+	retryClient := NewHTTPRetryClient(WithRetryBudgetRetry(0.2, 5))
+	builderClient := NewClientBuilder().WithRetryBudget(0.2, 5).Build()
+	genericClient := NewGenericClient[User](WithRetryBudget[User](0.2, 5))
+
+	clients := map[string]*http.Client{
+		"retry client":   retryClient,
+		"client builder": builderClient,
+		"generic client": genericClient.httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			rt := client.Transport.(*retryTransport)
+			if rt.budget == nil {
+				t.Fatal("Expected a retry budget")
+			}
+			assertEqual(t, 0.2, rt.budget.ratio)
+			assertEqual(t, 5, rt.budget.minPerSecond)
+		})
+	}
+
+	if rt := NewHTTPRetryClient().Transport.(*retryTransport); rt.budget != nil {
+		t.Error("Expected no retry budget by default")
+	}
+}
+
+func TestNewHTTPRetryClient_WithProxy_RoutesThroughProxy(t *testing.T) {
+	var proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute URL of the target
+		proxiedURL = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	client := NewHTTPRetryClient(WithProxyRetry(proxy.URL))
+
+	transport := client.Transport.(*retryTransport).Transport.(*http.Transport)
+	if transport == http.DefaultTransport {
+		t.Fatal("Expected http.DefaultTransport to be cloned, not mutated")
+	}
+
+	target, _ := http.NewRequest(http.MethodGet, "http://api.example.com/users", nil)
+	resolved, err := transport.Proxy(target)
+	if err != nil {
+		t.Fatalf("Proxy() failed: %v", err)
+	}
+	assertEqual(t, proxy.URL, resolved.String())
+
+	resp, err := client.Get("http://api.example.com/users")
+	if err != nil {
+		t.Fatalf("Get() through proxy failed: %v", err)
+	}
+	resp.Body.Close()
+
+	assertEqual(t, "http://api.example.com/users", proxiedURL)
+}
+
+func TestDefaultRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"connection reset", &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}, true},
+		{"context canceled", fmt.Errorf("dial: %w", context.Canceled), false},
+		{"context deadline", fmt.Errorf("dial: %w", context.DeadlineExceeded), false},
+		{"unknown authority", &url.Error{Op: "Get", URL: "https://x", Err: x509.UnknownAuthorityError{}}, false},
+		{"hostname mismatch", &url.Error{Op: "Get", URL: "https://x", Err: x509.HostnameError{Host: "x"}}, false},
+		{"expired certificate", x509.CertificateInvalidError{Reason: x509.Expired}, false},
+		{"certificate verification", &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}, false},
+		{"not a TLS server", tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, tt.want, DefaultRetryableError(tt.err))
+		})
+	}
+}
+
+func TestRetryTransport_RetryableErrorFunc(t *testing.T) {
+	certErr := &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}
+
+	tests := []struct {
+		name          string
+		err           error
+		retryable     func(error) bool
+		expectedCalls int
+	}{
+		{"certificate error is not retried", certErr, nil, 1},
+		{"network error is retried", errors.New("connection reset by peer"), nil, 3},
+		{"custom predicate retries certificate errors", certErr, func(error) bool { return true }, 3},
+		{"custom predicate stops network errors", errors.New("connection reset by peer"), func(error) bool { return false }, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			retryRT := &retryTransport{
+				Transport: &mockRoundTripper{
+					roundTripFunc: func(req *http.Request) (*http.Response, error) {
+						calls++
+						return nil, tt.err
+					},
+				},
+				RetryStrategy:  FixedDelay(time.Millisecond),
+				MaxRetries:     2,
+				retryableError: tt.retryable,
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+			resp, err := retryRT.RoundTrip(req)
+			if resp != nil {
+				t.Errorf("Expected nil response, got %v", resp)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected error wrapping %v, got %v", tt.err, err)
+			}
+			assertEqual(t, tt.expectedCalls, calls)
+		})
+	}
+}
+
+func TestWithRetryableErrorFunc_Options(t *testing.T) {
+	retryable := func(error) bool { return false }
+
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithRetryableErrorFuncRetry(retryable)),
+		"client builder": NewClientBuilder().WithRetryableErrorFunc(retryable).Build(),
+		"generic client": NewGenericClient[User](WithRetryableErrorFunc[User](retryable)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			rt := client.Transport.(*retryTransport)
+			if rt.retryableError == nil {
+				t.Fatal("Expected a retryable error function")
+			}
+			assertEqual(t, false, rt.isRetryableError(errors.New("no route to host"), false))
+		})
+	}
+}
+
+func TestRetryTransport_ConnectionReset(t *testing.T) {
+	resetErr := &url.Error{Op: "Post", URL: "https://example.com", Err: &net.OpError{
+		Op:  "read",
+		Net: "tcp",
+		Err: os.NewSyscallError("read", syscall.ECONNRESET),
+	}}
+	eofErr := fmt.Errorf("read response: %w", io.EOF)
+	strict := func(error) bool { return false }
+
+	tests := []struct {
+		name          string
+		err           error
+		reusedConn    bool
+		disabled      bool
+		expectedCalls int
+	}{
+		{"connection reset is retried despite a strict policy", resetErr, false, false, 3},
+		{"EOF on a reused connection is retried despite a strict policy", eofErr, true, false, 3},
+		{"EOF on a new connection follows the strict policy", eofErr, false, false, 1},
+		{"other errors follow the strict policy", errors.New("connection reset by peer"), true, false, 1},
+		{"disabled leaves connection resets to the policy", resetErr, false, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			retryRT := &retryTransport{
+				Transport: &mockRoundTripper{
+					roundTripFunc: func(req *http.Request) (*http.Response, error) {
+						calls++
+						if trace := httptrace.ContextClientTrace(req.Context()); trace != nil && trace.GotConn != nil {
+							trace.GotConn(httptrace.GotConnInfo{Reused: tt.reusedConn})
+						}
+						return nil, tt.err
+					},
+				},
+				RetryStrategy:          FixedDelay(time.Millisecond),
+				MaxRetries:             2,
+				retryableError:         strict,
+				noConnectionResetRetry: tt.disabled,
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+			_, err := retryRT.RoundTrip(req)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected error wrapping %v, got %v", tt.err, err)
+			}
+			assertEqual(t, tt.expectedCalls, calls)
+		})
+	}
+}
+
+func TestWithRetryOnConnectionReset_Options(t *testing.T) {
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithRetryOnConnectionResetRetry(false)),
+		"client builder": NewClientBuilder().WithRetryOnConnectionReset(false).Build(),
+		"generic client": NewGenericClient[User](WithRetryOnConnectionReset[User](false)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			rt := client.Transport.(*retryTransport)
+			assertTrue(t, rt.noConnectionResetRetry)
+		})
+	}
+
+	rt := NewClientBuilder().Build().Transport.(*retryTransport)
+	assertEqual(t, false, rt.noConnectionResetRetry)
+}
+
+func TestWithAbsoluteMaxDelay_Options(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1)%2 == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithAbsoluteMaxDelayRetry(20*time.Millisecond), WithRespectRetryAfterRetry(true)),
+		"client builder": NewClientBuilder().WithAbsoluteMaxDelay(20 * time.Millisecond).WithRespectRetryAfter(true).Build(),
+		"generic client": NewGenericClient[User](WithAbsoluteMaxDelay[User](20*time.Millisecond), WithRespectRetryAfter[User](true)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			calls.Store(0)
+			start := time.Now()
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Get() failed: %v", err)
+			}
+			resp.Body.Close()
+
+			assertEqual(t, http.StatusOK, resp.StatusCode)
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Expected the Retry-After delay to be clamped, took %v", elapsed)
+			}
+		})
+	}
+
+	t.Run("Clamps the strategy delay", func(t *testing.T) {
+		rt := &retryTransport{absoluteMaxDelay: 50 * time.Millisecond}
+		delay, computed, source := rt.retryDelay(FixedDelay(time.Minute), 0, nil)
+
+		assertEqual(t, 50*time.Millisecond, delay)
+		assertEqual(t, time.Minute, computed)
+		assertEqual(t, delaySourceCapped, source)
+	})
+}
+
+func TestWithRetryAfterMaxWait_Options(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", r.URL.Query().Get("wait"))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithRetryAfterMaxWaitRetry(time.Minute), WithMaxRetriesRetry(1)),
+		"client builder": NewClientBuilder().WithRetryAfterMaxWait(time.Minute).WithMaxRetries(1).Build(),
+		"generic client": NewGenericClient[User](WithRetryAfterMaxWait[User](time.Minute), WithMaxRetries[User](1)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			calls.Store(0)
+			start := time.Now()
+
+			_, err := client.Get(server.URL + "?wait=3600")
+			if !errors.Is(err, ErrRetryAfterTooLong) {
+				t.Fatalf("Expected ErrRetryAfterTooLong, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "status 429") {
+				t.Errorf("Expected the status code in the error, got %v", err)
+			}
+			assertEqual(t, int32(1), calls.Load())
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Expected to give up without waiting, took %v", elapsed)
+			}
+		})
+	}
+
+	t.Run("Retry-After beyond the max delay is honored up to the bound", func(t *testing.T) {
+		rt := &retryTransport{maxDelay: 10 * time.Second, retryAfterMaxWait: time.Minute}
+		header := http.Header{"Retry-After": []string{"30"}}
+
+		delay, computed, source := rt.retryDelay(FixedDelay(time.Millisecond), 0, &http.Response{StatusCode: http.StatusTooManyRequests, Header: header})
+
+		assertEqual(t, 30*time.Second, delay)
+		assertEqual(t, 30*time.Second, computed)
+		assertEqual(t, delaySourceRetryAfter, source)
+	})
+
+	t.Run("Retry client waits beyond its max delay", func(t *testing.T) {
+		calls.Store(0)
+		client := NewHTTPRetryClient(
+			WithRetryAfterMaxWaitRetry(time.Minute),
+			WithRetryMaxDelayRetry(10*time.Millisecond),
+			WithRetryStrategyRetry(FixedDelay(time.Millisecond)),
+			WithMaxRetriesRetry(1),
+		)
+		start := time.Now()
+
+		_, err := client.Get(server.URL + "?wait=1")
+		if !errors.Is(err, ErrAllRetriesFailed) {
+			t.Fatalf("Expected ErrAllRetriesFailed, got %v", err)
+		}
+		assertEqual(t, int32(2), calls.Load())
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Errorf("Expected to wait for Retry-After, took %v", elapsed)
+		}
+	})
+
+	t.Run("Retry-After within the bound is honored", func(t *testing.T) {
+		calls.Store(0)
+		_, err := clients["client builder"].Get(server.URL + "?wait=0")
+		if !errors.Is(err, ErrAllRetriesFailed) {
+			t.Fatalf("Expected ErrAllRetriesFailed, got %v", err)
+		}
+		assertEqual(t, int32(2), calls.Load())
+	})
+}
+
+func TestWithDefaultHeadersRetry(t *testing.T) {
+	var received []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Clone())
+		if len(received) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPRetryClient(
+		WithDefaultHeadersRetry(map[string]string{"user-agent": "my-service/1.0", "X-Api-Version": "2"}),
+		WithDefaultHeadersRetry(map[string]string{"Accept": "application/json"}),
+		WithRetryStrategyRetry(func(int) time.Duration { return time.Millisecond }),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("X-Api-Version", "3")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() failed: %v", err)
+	}
+	resp.Body.Close()
+
+	// Every attempt carries the default headers, without overriding the request's own
+	assertEqual(t, 2, len(received))
+	for _, header := range received {
+		assertEqual(t, "my-service/1.0", header.Get("User-Agent"))
+		assertEqual(t, "application/json", header.Get("Accept"))
+		assertEqual(t, "3", header.Get("X-Api-Version"))
+	}
+
+	// The caller's request is not modified
+	assertEqual(t, "", req.Header.Get("User-Agent"))
+	assertEqual(t, 1, len(req.Header))
+}
+
+func TestWithBodyReadTimeout_Options(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/stall":
+			// Headers and the first chunk arrive quickly, then the body stalls
+			_, _ = w.Write([]byte(`{"id":`))
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		default:
+			// A slow but steady stream, each chunk well within the timeout
+			for i := 0; i < 4; i++ {
+				_, _ = w.Write([]byte("chunk"))
+				w.(http.Flusher).Flush()
+				time.Sleep(30 * time.Millisecond)
+			}
+		}
+	}))
+	defer server.Close()
+
+	timeout := 100 * time.Millisecond
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithBodyReadTimeoutRetry(timeout)),
+		"client builder": NewClientBuilder().WithBodyReadTimeout(timeout).Build(),
+		"generic client": NewGenericClient[User](WithBodyReadTimeout[User](timeout)).httpClient.(*http.Client),
+		"phase timeouts": NewClientBuilder().WithPhaseTimeouts(PhaseTimeouts{BodyRead: timeout}).Build(),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			resp, err := client.Get(server.URL + "/stall")
+			if err != nil {
+				t.Fatalf("Get() failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			start := time.Now()
+			_, err = io.ReadAll(resp.Body)
+			if !errors.Is(err, ErrBodyReadTimeout) {
+				t.Fatalf("Expected ErrBodyReadTimeout, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Expected the stalled read to be aborted, took %v", elapsed)
+			}
+		})
+	}
+
+	t.Run("Steady stream and slow consumer", func(t *testing.T) {
+		resp, err := clients["client builder"].Get(server.URL + "/stream")
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		// Time spent by the caller between reads does not count
+		time.Sleep(2 * timeout)
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("ReadAll() failed: %v", err)
+		}
+		assertEqual(t, strings.Repeat("chunk", 4), string(body))
+	})
+}
+
+func TestWithResponseHeaderTimeout_Options(t *testing.T) {
+	timeout := 50 * time.Millisecond
+	assertEqual(t, timeout, baseTransport(t, NewClientBuilder().WithResponseHeaderTimeout(timeout).Build()).ResponseHeaderTimeout)
+	assertEqual(t, timeout, baseTransport(t, NewGenericClient[User](WithResponseHeaderTimeout[User](timeout)).httpClient.(*http.Client)).ResponseHeaderTimeout)
+	assertEqual(t, time.Duration(0), baseTransport(t, NewClientBuilder().Build()).ResponseHeaderTimeout)
+}
+
+func TestWithAttemptsHeader_Options(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every third call succeeds, so each request takes three attempts
+		if calls.Add(1)%3 != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	fast := func(int) time.Duration { return time.Millisecond }
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithAttemptsHeaderRetry("X-Httpx-Attempts"), WithRetryStrategyRetry(fast)),
+		"client builder": NewClientBuilder().WithAttemptsHeader("X-Httpx-Attempts").WithRetryStrategyFunc(fast).Build(),
+		"generic client": NewGenericClient[User](WithAttemptsHeader[User]("X-Httpx-Attempts"), WithRetryStrategyFunc[User](fast)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			calls.Store(0)
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Get() failed: %v", err)
+			}
+			resp.Body.Close()
+
+			assertEqual(t, http.StatusOK, resp.StatusCode)
+			assertEqual(t, "3", resp.Header.Get("X-Httpx-Attempts"))
+		})
+	}
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		calls.Store(2)
+		resp, err := NewClientBuilder().Build().Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		resp.Body.Close()
+
+		assertEqual(t, "", resp.Header.Get("X-Httpx-Attempts"))
+	})
+}
+
+func TestRetryTransport_NoRetryOnContextErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      func() (context.Context, context.CancelFunc)
+		err      error
+		expected error
+	}{
+		{
+			name: "request deadline already expired",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+			},
+			err:      fmt.Errorf("dial tcp: %w", context.DeadlineExceeded),
+			expected: context.DeadlineExceeded,
+		},
+		{
+			name: "deadline from a wrapped transport",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			err:      fmt.Errorf("upstream call: %w", context.DeadlineExceeded),
+			expected: context.DeadlineExceeded,
+		},
+		{
+			name: "cancellation from a wrapped transport",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			err:      fmt.Errorf("upstream call: %w", context.Canceled),
+			expected: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			retryRT := &retryTransport{
+				Transport: &mockRoundTripper{
+					roundTripFunc: func(req *http.Request) (*http.Response, error) {
+						atomic.AddInt32(&attempts, 1)
+						return nil, tt.err
+					},
+				},
+				MaxRetries:    5,
+				RetryStrategy: FixedDelay(time.Millisecond),
+				// Even a predicate that retries everything must not retry context errors
+				retryableError: func(error) bool { return true },
+			}
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+			_, err := retryRT.RoundTrip(req)
+
+			assertEqual(t, int32(1), atomic.LoadInt32(&attempts))
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if errors.Is(err, ErrAllRetriesFailed) {
+				t.Errorf("Error should not be wrapped as retries exhausted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_MaxResponseTime(t *testing.T) {
+	t.Run("retry sequence exceeding the limit", func(t *testing.T) {
+		var attempts int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := NewHTTPRetryClient(
+			WithMaxRetriesRetry(10),
+			WithRetryStrategyRetry(FixedDelay(50*time.Millisecond)),
+			WithMaxResponseTimeRetry(120*time.Millisecond),
+		)
+
+		start := time.Now()
+		resp, err := client.Get(server.URL)
+		elapsed := time.Since(start)
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if !errors.Is(err, ErrResponseTooSlow) {
+			t.Fatalf("Expected ErrResponseTooSlow, got %v", err)
+		}
+		if elapsed > time.Second {
+			t.Errorf("Expected the request to stop at the maximum response time, took %v", elapsed)
+		}
+		if n := atomic.LoadInt32(&attempts); n < 2 || n > 4 {
+			t.Errorf("Expected 2-4 attempts within the limit, got %d", n)
+		}
+	})
+
+	t.Run("slow attempt is aborted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		}))
+		defer server.Close()
+
+		client := NewClientBuilder().WithMaxResponseTime(100 * time.Millisecond).Build()
+
+		_, err := client.Get(server.URL)
+		if !errors.Is(err, ErrResponseTooSlow) {
+			t.Fatalf("Expected ErrResponseTooSlow, got %v", err)
+		}
+	})
+
+	t.Run("fast response body stays readable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("OK"))
+		}))
+		defer server.Close()
+
+		client := NewHTTPRetryClient(WithMaxResponseTimeRetry(time.Second))
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("ReadAll() failed: %v", err)
+		}
+		assertEqual(t, "OK", string(body))
+	})
+
+	t.Run("parent context cancellation is not reported as too slow", func(t *testing.T) {
+		retryRT := &retryTransport{
+			Transport: &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					return nil, req.Context().Err()
+				},
+			},
+			MaxRetries:      3,
+			RetryStrategy:   FixedDelay(time.Millisecond),
+			maxResponseTime: time.Second,
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+		_, err := retryRT.RoundTrip(req)
+		if !errors.Is(err, context.Canceled) || errors.Is(err, ErrResponseTooSlow) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestWithMaxResponseTime_Options(t *testing.T) {
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithMaxResponseTimeRetry(3 * time.Second)),
+		"client builder": NewClientBuilder().WithMaxResponseTime(3 * time.Second).Build(),
+		"generic client": NewGenericClient[User](WithMaxResponseTime[User](3 * time.Second)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			assertEqual(t, 3*time.Second, client.Transport.(*retryTransport).maxResponseTime)
+		})
+	}
+}
+
+func TestRetryTransport_PerAttemptTimeout(t *testing.T) {
+	t.Run("slow attempt is retried with a fresh timeout", func(t *testing.T) {
+		var attempts int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(2 * time.Second):
+				}
+				return
+			}
+			_, _ = w.Write([]byte("OK"))
+		}))
+		defer server.Close()
+
+		client := NewHTTPRetryClient(
+			WithMaxRetriesRetry(2),
+			WithRetryStrategyRetry(FixedDelay(time.Millisecond)),
+			WithPerAttemptTimeoutRetry(100*time.Millisecond),
+		)
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read body: %v", err)
+		}
+		assertEqual(t, "OK", string(body))
+		assertEqual(t, int32(2), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("every attempt timing out", func(t *testing.T) {
+		var attempts int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		}))
+		defer server.Close()
+
+		client := NewClientBuilder().
+			WithMaxRetries(2).
+			WithRetryStrategyFunc(FixedDelay(time.Millisecond)).
+			WithPerAttemptTimeout(50 * time.Millisecond).
+			Build()
+
+		_, err := client.Get(server.URL)
+		if !errors.Is(err, ErrAttemptTimeout) {
+			t.Fatalf("Expected ErrAttemptTimeout, got %v", err)
+		}
+		assertEqual(t, int32(3), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("cancelled request is not retried", func(t *testing.T) {
+		var attempts int32
+		retryRT := &retryTransport{
+			Transport: &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					atomic.AddInt32(&attempts, 1)
+					<-req.Context().Done()
+					return nil, req.Context().Err()
+				},
+			},
+			MaxRetries:        2,
+			RetryStrategy:     FixedDelay(time.Millisecond),
+			perAttemptTimeout: time.Second,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+		_, err := retryRT.RoundTrip(req)
+		if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrAttemptTimeout) {
+			t.Fatalf("Expected the request deadline error, got %v", err)
+		}
+		assertEqual(t, int32(1), atomic.LoadInt32(&attempts))
+	})
+}
+
+func TestClientBuilder_TimeoutPerAttempt(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		time.Sleep(600 * time.Millisecond)
+		if n == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	// A scaled-down version of two 3s attempts with a 5s per-attempt budget: two attempts of
+	// 600ms each take longer than the 1s timeout (the minimum valid one) combined, but each
+	// one completes within its own 1s budget
+	client := NewClientBuilder().
+		WithTimeout(time.Second).
+		WithMaxRetries(1).
+		WithRetryStrategyFunc(FixedDelay(time.Millisecond)).
+		Build()
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	assertEqual(t, http.StatusOK, resp.StatusCode)
+	assertEqual(t, int32(2), atomic.LoadInt32(&attempts))
+
+	// An explicit per-attempt timeout overrides the client timeout
+	client = NewClientBuilder().WithTimeout(5 * time.Second).WithPerAttemptTimeout(time.Second).Build()
+	assertEqual(t, time.Second, attemptTimeout(t, client))
+}
+
+func TestWithPerAttemptTimeout_Options(t *testing.T) {
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithPerAttemptTimeoutRetry(2 * time.Second)),
+		"client builder": NewClientBuilder().WithPerAttemptTimeout(2 * time.Second).Build(),
+		"generic client": NewGenericClient[User](WithPerAttemptTimeout[User](2 * time.Second)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			assertEqual(t, 2*time.Second, client.Transport.(*retryTransport).perAttemptTimeout)
+		})
+	}
+}
+
+func TestRetryTransport_ReusedRequest(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"Jane"}` {
+			t.Errorf("Attempt %d received body %q", calls.Load()+1, body)
+		}
+
+		// The first attempt of every call fails, so each call retries once
+		if calls.Add(1)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewHTTPRetryClient(WithRetryStrategyRetry(FixedDelay(time.Millisecond)))
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"name":"Jane"}`))
+	originalBody := req.Body
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Call %d failed: %v", i+1, err)
+		}
+		resp.Body.Close()
+
+		assertEqual(t, http.StatusCreated, resp.StatusCode)
+		assertTrue(t, req.Body == originalBody)
+	}
+
+	assertEqual(t, int32(4), calls.Load())
+}
+
+func TestRetryTransport_BodyWithoutGetBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		expectedLog string
+	}{
+		{name: "Retryable status", expectedLog: "returned server error, not retrying because its body cannot be replayed"},
+		{name: "Retryable error", err: errors.New("connection refused"), expectedLog: "failed, not retrying because its body cannot be replayed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []string
+			var logBuf bytes.Buffer
+
+			retryRT := &retryTransport{
+				Transport: &mockRoundTripper{
+					roundTripFunc: func(req *http.Request) (*http.Response, error) {
+						body, _ := io.ReadAll(req.Body)
+						received = append(received, string(body))
+
+						if tt.err != nil {
+							return nil, tt.err
+						}
+
+						return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header)}, nil
+					},
+				},
+				MaxRetries:    3,
+				RetryStrategy: FixedDelay(1 * time.Millisecond),
+				logger:        slog.New(slog.NewTextHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelWarn})),
+			}
+
+			// A streaming body has no GetBody, so a retry would send it empty
+			req, _ := http.NewRequest(http.MethodPost, "http://example.com", io.NopCloser(strings.NewReader("payload")))
+			if req.GetBody != nil {
+				t.Fatal("Expected a request without GetBody")
+			}
+
+			resp, err := retryRT.RoundTrip(req)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("Expected the transport error, got %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("RoundTrip() failed: %v", err)
+				}
+				resp.Body.Close()
+				assertEqual(t, http.StatusServiceUnavailable, resp.StatusCode)
+			}
+
+			assertEqual(t, []string{"payload"}, received)
+			if !strings.Contains(logBuf.String(), tt.expectedLog) {
+				t.Errorf("Expected warning %q, got: %s", tt.expectedLog, logBuf.String())
+			}
+		})
+	}
+}
+
+func TestRetryTransport_MaxRetryBodyBytes(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		expectedAttempts int32
+		expectedStatus   int
+	}{
+		{name: "Small body is buffered and retried", body: "small", expectedAttempts: 2, expectedStatus: http.StatusOK},
+		{name: "Large body is sent once", body: strings.Repeat("x", 64), expectedAttempts: 1, expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			var received []string
+
+			retryRT := &retryTransport{
+				Transport: &mockRoundTripper{
+					roundTripFunc: func(req *http.Request) (*http.Response, error) {
+						body, _ := io.ReadAll(req.Body)
+						received = append(received, string(body))
+
+						status := http.StatusOK
+						if atomic.AddInt32(&attempts, 1) == 1 {
+							status = http.StatusServiceUnavailable
+						}
+
+						return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header)}, nil
+					},
+				},
+				MaxRetries:        2,
+				RetryStrategy:     FixedDelay(1 * time.Millisecond),
+				maxRetryBodyBytes: 16,
+			}
+
+			// A streaming body has no GetBody
+			req, _ := http.NewRequest(http.MethodPost, "http://example.com", io.NopCloser(strings.NewReader(tt.body)))
+			if req.GetBody != nil {
+				t.Fatal("Expected a request without GetBody")
+			}
+
+			resp, err := retryRT.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() failed: %v", err)
+			}
+			resp.Body.Close()
+
+			assertEqual(t, tt.expectedStatus, resp.StatusCode)
+			assertEqual(t, tt.expectedAttempts, atomic.LoadInt32(&attempts))
+			for i, body := range received {
+				if body != tt.body {
+					t.Errorf("Attempt %d sent body %q, want %q", i+1, body, tt.body)
+				}
+			}
+		})
+	}
+}
+
+func TestWithMaxRetryBodyBytes_Options(t *testing.T) {
+	clients := map[string]*http.Client{
+		"retry client":   NewHTTPRetryClient(WithMaxRetryBodyBytesRetry(1024)),
+		"client builder": NewClientBuilder().WithMaxRetryBodyBytes(1024).Build(),
+		"generic client": NewGenericClient[User](WithMaxRetryBodyBytes[User](1024)).httpClient.(*http.Client),
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			assertEqual(t, 1024, client.Transport.(*retryTransport).maxRetryBodyBytes)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrSchemaViolation is returned by Execute when a response body does not match the schema
+// set with WithResponseSchema.
+var ErrSchemaViolation = errors.New("response does not match schema")
+
+// schemaTypeNames are the JSON Schema type names supported by WithResponseSchema.
+var schemaTypeNames = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// WithResponseSchema validates successful response bodies against a JSON Schema before
+// they are decoded into T, to catch upstream contract drift, e.g. in contract tests. A
+// body that does not match fails Execute with an error wrapping ErrSchemaViolation that
+// lists every violation with its location, such as `$.items[2].id: expected integer, got
+// string`. Only a subset of JSON Schema is supported:
+//
+//   - type: a type name or an array of them; "integer" matches numbers without a fraction
+//   - properties: schemas of object properties, which are only checked when present
+//   - required: names of properties an object must have
+//   - items: schema of every array element
+//   - enum: the allowed values
+//
+// Other keywords, such as $ref, pattern or minimum, are ignored. The body must be JSON,
+// even with a decoder set with WithDecoder. Execute fails for every request when the
+// schema itself is invalid. Pass nil to disable validation (default behavior).
+func WithResponseSchema[T any](schema []byte) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		if schema == nil {
+			c.validateSchema = nil
+			return
+		}
+
+		parsed, err := parseJSONSchema(schema)
+		if err != nil {
+			c.validateSchema = func([]byte) error {
+				return fmt.Errorf("invalid response schema: %w", err)
+			}
+			return
+		}
+
+		c.validateSchema = parsed.validateBody
+	}
+}
+
+// jsonSchema is the subset of a JSON Schema supported by WithResponseSchema.
+type jsonSchema struct {
+	Type       schemaTypes            `json:"type"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Required   []string               `json:"required"`
+	Items      *jsonSchema            `json:"items"`
+	Enum       []any                  `json:"enum"`
+}
+
+// schemaTypes holds the value of the "type" keyword, a single type name or an array of them.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = schemaTypes{name}
+		return nil
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings")
+	}
+	*t = names
+
+	return nil
+}
+
+// parseJSONSchema parses schema and checks the type names it uses.
+func parseJSONSchema(schema []byte) (*jsonSchema, error) {
+	var parsed jsonSchema
+	if err := json.Unmarshal(schema, &parsed); err != nil {
+		return nil, err
+	}
+
+	if err := parsed.check("$"); err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
+
+// check returns an error for the first unsupported type name in s or its subschemas.
+func (s *jsonSchema) check(path string) error {
+	for _, name := range s.Type {
+		if !slices.Contains(schemaTypeNames, name) {
+			return fmt.Errorf("%s: unsupported type %q", path, name)
+		}
+	}
+
+	for name, property := range s.Properties {
+		if property == nil {
+			continue
+		}
+
+		if err := property.check(path + "." + name); err != nil {
+			return err
+		}
+	}
+
+	if s.Items != nil {
+		return s.Items.check(path + "[]")
+	}
+
+	return nil
+}
+
+// validateBody returns an error wrapping ErrSchemaViolation listing the violations of body.
+func (s *jsonSchema) validateBody(body []byte) error {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("%w: invalid JSON: %w", ErrSchemaViolation, err)
+	}
+
+	violations := s.validate(value, "$", nil)
+	if len(violations) > 0 {
+		return fmt.Errorf("%w: %s", ErrSchemaViolation, strings.Join(violations, "; "))
+	}
+
+	return nil
+}
+
+// validate appends the violations of value, found at path, to violations and returns them.
+func (s *jsonSchema) validate(value any, path string, violations []string) []string {
+	if len(s.Type) > 0 && !s.matchesType(value) {
+		return append(violations, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.Type, " or "), jsonTypeName(value)))
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(allowed any) bool { return reflect.DeepEqual(allowed, value) }) {
+		violations = append(violations, fmt.Sprintf("%s: value %s not in enum", path, compactJSON(value)))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				violations = append(violations, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+
+		// Properties are visited in sorted order, so violations are listed deterministically
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			property, ok := v[name]
+			if !ok || s.Properties[name] == nil {
+				continue
+			}
+
+			violations = s.Properties[name].validate(property, path+"."+name, violations)
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				violations = s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	}
+
+	return violations
+}
+
+// matchesType reports whether value is of one of the types of s.
+func (s *jsonSchema) matchesType(value any) bool {
+	name := jsonTypeName(value)
+
+	for _, allowed := range s.Type {
+		switch {
+		case allowed == name:
+			return true
+		case allowed == "integer" && name == "number":
+			if number := value.(float64); number == math.Trunc(number) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// jsonTypeName returns the JSON Schema type name of a value decoded by encoding/json.
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// compactJSON returns value encoded as JSON, for error messages.
+func compactJSON(value any) string {
+	data, _ := json.Marshal(value)
+
+	return string(data)
+}
@@ -0,0 +1,81 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGenericClient_WithResponseSchema(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"required": ["id", "name"],
+		"properties": {
+			"id": {"type": "integer"},
+			"name": {"type": "string"},
+			"status": {"enum": ["active", "disabled"]},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"manager": {"type": ["object", "null"], "required": ["id"]}
+		}
+	}`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/valid":
+			_, _ = w.Write([]byte(`{"id":1,"name":"John","status":"active","tags":["a"],"manager":null,"extra":true}`))
+		case "/drift":
+			_, _ = w.Write([]byte(`{"id":"1","status":"deleted","tags":["a",2],"manager":{}}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":1.5,"name":"John"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[User](WithResponseSchema[User](schema))
+
+	t.Run("Valid body", func(t *testing.T) {
+		resp, err := client.Get(server.URL + "/valid")
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		assertEqual(t, "John", resp.Data.Name)
+	})
+
+	t.Run("Violations", func(t *testing.T) {
+		_, err := client.Get(server.URL + "/drift")
+		if !errors.Is(err, ErrSchemaViolation) {
+			t.Fatalf("Expected ErrSchemaViolation, got %v", err)
+		}
+
+		for _, want := range []string{
+			`$: missing required property "name"`,
+			"$.id: expected integer, got string",
+			`$.manager: missing required property "id"`,
+			`$.status: value "deleted" not in enum`,
+			"$.tags[1]: expected string, got number",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("Expected error containing %q, got %v", want, err)
+			}
+		}
+	})
+
+	t.Run("Fractional integer", func(t *testing.T) {
+		_, err := client.Get(server.URL + "/fraction")
+		if err == nil || !strings.Contains(err.Error(), "$.id: expected integer, got number") {
+			t.Errorf("Expected an integer violation, got %v", err)
+		}
+	})
+
+	t.Run("Invalid schema", func(t *testing.T) {
+		client := NewGenericClient[User](WithResponseSchema[User]([]byte(`{"type":"uuid"}`)))
+
+		_, err := client.Get(server.URL + "/valid")
+		if err == nil || !strings.Contains(err.Error(), `invalid response schema: $: unsupported type "uuid"`) {
+			t.Errorf("Expected an invalid schema error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,272 @@
+package httpx
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxEventStreamLineBytes is the maximum length of a single line in a server-sent events stream.
+const maxEventStreamLineBytes = 1 << 20
+
+// Event is a server-sent event received from a text/event-stream response.
+type Event struct {
+	// ID is the last event ID set by the stream, sent as Last-Event-ID when reconnecting.
+	ID string
+
+	// Event is the event type, "message" when the stream does not set one.
+	Event string
+
+	// Data is the event payload; multiple data lines are joined with "\n".
+	Data string
+}
+
+// WithSSEReconnect makes SubscribeSSE reconnect when a server-sent events stream ends or
+// is interrupted, waiting delay before each attempt, or the reconnection time sent by the
+// server in a "retry" field. Reconnection requests carry the Last-Event-ID header so the
+// server can resume the stream. Reconnection stops when the request context is done, or
+// when the server answers with an error status, a 204 No Content or a non-event-stream
+// response. Pass 0 to end the subscription when the stream ends (default behavior).
+func WithSSEReconnect[T any](delay time.Duration) GenericClientOption[T] {
+	return func(c *GenericClient[T]) {
+		c.sseReconnectDelay = delay
+	}
+}
+
+// SubscribeSSE sends the request and returns a channel of the server-sent events read from
+// the text/event-stream response, parsing the "data", "event", "id" and "retry" fields as
+// defined by the SSE specification. The channel is closed when the stream ends, or when the
+// request context is done, which is how callers unsubscribe. An error is returned if the
+// initial request fails, the server answers with an error status, or the response is not an
+// event stream. A 204 No Content response yields a closed channel.
+//
+// The client timeout also applies to reading the stream, so long-lived subscriptions need a
+// client with a suitable timeout, or WithSSEReconnect to resume after the timeout.
+func (c *GenericClient[T]) SubscribeSSE(req *http.Request) (<-chan Event, error) {
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+
+	req, err := c.prepareRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	resp, err := c.openEventStream(req)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go c.consumeEventStream(req, resp, events)
+
+	return events, nil
+}
+
+// openEventStream sends req and returns the event stream response, or nil when the server
+// answered 204 No Content to signal that there are no events.
+func (c *GenericClient[T]) openEventStream(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute http request: %w", err)
+	}
+
+	return c.checkEventStream(resp)
+}
+
+// checkEventStream returns resp if it is an event stream, nil for a 204 No Content
+// response, or an error otherwise. The body is closed unless resp is returned.
+func (c *GenericClient[T]) checkEventStream(resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+
+		return nil, c.errorFromResponse(resp)
+	}
+
+	if resp.StatusCode == http.StatusNoContent {
+		resp.Body.Close()
+
+		return nil, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		resp.Body.Close()
+
+		if mediaType == "" {
+			mediaType = "no content type"
+		}
+
+		return nil, fmt.Errorf("%w: expected text/event-stream, got %s", ErrUnexpectedContentType, mediaType)
+	}
+
+	return resp, nil
+}
+
+// consumeEventStream reads events from resp into events, reconnecting if configured,
+// and closes events when the subscription ends.
+func (c *GenericClient[T]) consumeEventStream(req *http.Request, resp *http.Response, events chan<- Event) {
+	defer close(events)
+
+	ctx := req.Context()
+	stream := &eventStreamReader{}
+
+	for resp != nil {
+		err := stream.read(ctx, resp.Body, events)
+		resp.Body.Close()
+
+		if ctx.Err() != nil || c.sseReconnectDelay <= 0 {
+			return
+		}
+
+		if c.logger != nil {
+			c.logger.Debug("Event stream ended, reconnecting",
+				"url", req.URL.String(),
+				"last_event_id", stream.lastEventID,
+				"error", err,
+			)
+		}
+
+		resp = c.reconnectEventStream(req, stream)
+	}
+}
+
+// reconnectEventStream waits for the reconnection delay and reopens the event stream,
+// retrying while the connection fails. It returns nil when the subscription must end.
+func (c *GenericClient[T]) reconnectEventStream(req *http.Request, stream *eventStreamReader) *http.Response {
+	ctx := req.Context()
+
+	for {
+		delay := c.sseReconnectDelay
+		if stream.retry > 0 {
+			delay = stream.retry
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		next := req.Clone(ctx)
+		if stream.lastEventID != "" {
+			next.Header.Set("Last-Event-ID", stream.lastEventID)
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil
+			}
+
+			next.Body = body
+		}
+
+		resp, err := c.httpClient.Do(next)
+		if err != nil {
+			// The server may be temporarily unreachable, keep trying until the context is done
+			if c.logger != nil {
+				c.logger.Debug("Event stream reconnection failed", "url", req.URL.String(), "error", err)
+			}
+
+			continue
+		}
+
+		resp, err = c.checkEventStream(resp)
+		if err != nil {
+			if c.logger != nil {
+				c.logger.Warn("Event stream reconnection rejected, ending subscription", "url", req.URL.String(), "error", err)
+			}
+
+			return nil
+		}
+
+		return resp
+	}
+}
+
+// eventStreamReader parses a text/event-stream body, keeping the state that
+// persists across reconnections.
+type eventStreamReader struct {
+	lastEventID string
+	retry       time.Duration
+}
+
+// read parses events from body and sends them to events until the body ends,
+// a read error occurs, or ctx is done.
+func (r *eventStreamReader) read(ctx context.Context, body io.Reader, events chan<- Event) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 4096), maxEventStreamLineBytes)
+
+	var eventType string
+	var data bytes.Buffer
+	hasData := false
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			// An empty line dispatches the event
+			if hasData {
+				event := Event{ID: r.lastEventID, Event: eventType, Data: data.String()}
+				if event.Event == "" {
+					event.Event = "message"
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			eventType = ""
+			data.Reset()
+			hasData = false
+
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			// Comment, used by servers as a keep-alive
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				r.lastEventID = value
+			}
+		case "retry":
+			if millis, err := strconv.Atoi(value); err == nil && millis >= 0 {
+				r.retry = time.Duration(millis) * time.Millisecond
+			}
+		}
+	}
+
+	return scanner.Err()
+}
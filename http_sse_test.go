@@ -0,0 +1,156 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func collectEvents(t *testing.T, events <-chan Event) []Event {
+	t.Helper()
+
+	var received []Event
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return received
+			}
+			received = append(received, event)
+		case <-timeout:
+			t.Fatal("Timed out waiting for the event channel to close")
+		}
+	}
+}
+
+func TestGenericClient_SubscribeSSE(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("Accept = %q, want text/event-stream", r.Header.Get("Accept"))
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(": keep-alive\n\n" +
+			"data: first\n\n" +
+			"event: update\nid: 42\ndata: line one\ndata:line two\n\n" +
+			"id: 43\n\n" +
+			"data: {\"id\":1}\r\n\r\n"))
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[User]()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() failed: %v", err)
+	}
+
+	events, err := client.SubscribeSSE(req)
+	if err != nil {
+		t.Fatalf("SubscribeSSE() failed: %v", err)
+	}
+
+	expected := []Event{
+		{Event: "message", Data: "first"},
+		{ID: "42", Event: "update", Data: "line one\nline two"},
+		{ID: "43", Event: "message", Data: `{"id":1}`},
+	}
+	assertEqual(t, expected, collectEvents(t, events))
+}
+
+func TestGenericClient_SubscribeSSE_Reconnect(t *testing.T) {
+	var connections int32
+	var lastEventIDs []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+
+		switch atomic.AddInt32(&connections, 1) {
+		case 1:
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("retry: 5\nid: 1\ndata: before\n\n"))
+		case 2:
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("id: 2\ndata: after\n\n"))
+		default:
+			// No more events: the client must stop reconnecting
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[User](WithSSEReconnect[User](time.Hour))
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() failed: %v", err)
+	}
+
+	events, err := client.SubscribeSSE(req)
+	if err != nil {
+		t.Fatalf("SubscribeSSE() failed: %v", err)
+	}
+
+	// The server's retry field (5ms) takes precedence over the configured delay
+	expected := []Event{
+		{ID: "1", Event: "message", Data: "before"},
+		{ID: "2", Event: "message", Data: "after"},
+	}
+	assertEqual(t, expected, collectEvents(t, events))
+	assertEqual(t, []string{"", "1", "2"}, lastEventIDs)
+}
+
+func TestGenericClient_SubscribeSSE_Errors(t *testing.T) {
+	t.Run("Error status", func(t *testing.T) {
+		client := NewGenericClient[User](WithHTTPClient[User](&capturingClient{statusCode: http.StatusNotFound}))
+
+		_, err := client.SubscribeSSE(httptest.NewRequest(http.MethodGet, "http://example.com/events", nil))
+
+		var errResp *ErrorResponse
+		if !errors.As(err, &errResp) {
+			t.Fatalf("Expected *ErrorResponse, got %v", err)
+		}
+		assertEqual(t, http.StatusNotFound, errResp.StatusCode)
+	})
+
+	t.Run("Not an event stream", func(t *testing.T) {
+		client := NewGenericClient[User](WithHTTPClient[User](&capturingClient{
+			body:   "<html></html>",
+			header: http.Header{"Content-Type": {"text/html"}},
+		}))
+
+		_, err := client.SubscribeSSE(httptest.NewRequest(http.MethodGet, "http://example.com/events", nil))
+		if !errors.Is(err, ErrUnexpectedContentType) {
+			t.Fatalf("Expected ErrUnexpectedContentType, got %v", err)
+		}
+	})
+
+	t.Run("Context cancellation closes the channel", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatalf("NewRequest() failed: %v", err)
+		}
+
+		client := NewGenericClient[User](WithSSEReconnect[User](time.Millisecond))
+		events, err := client.SubscribeSSE(req)
+		if err != nil {
+			t.Fatalf("SubscribeSSE() failed: %v", err)
+		}
+
+		cancel()
+		assertEqual(t, 0, len(collectEvents(t, events)))
+	})
+}
@@ -0,0 +1,86 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// ClientStats is a snapshot of the cumulative request counters of a GenericClient,
+// returned by GenericClient.Stats.
+type ClientStats struct {
+	Requests  uint64 // Requests executed with Execute or ExecuteRaw
+	Retries   uint64 // Attempts made after the first attempt of a request
+	Successes uint64 // Requests that returned a response without error
+	Failures  uint64 // Requests that returned an error or a response with an error status
+
+	// AttemptCounts maps a number of attempts to the number of requests that took it.
+	// Requests that never reached the retry layer, e.g. rejected before being sent or
+	// sent through a client set with WithHTTPClient, are counted under 0 attempts.
+	AttemptCounts map[int]uint64
+}
+
+// clientStats holds the cumulative request counters of a client.
+// It is safe for concurrent use.
+type clientStats struct {
+	requests  atomic.Uint64
+	retries   atomic.Uint64
+	successes atomic.Uint64
+	failures  atomic.Uint64
+	attempts  sync.Map // int -> *atomic.Uint64
+}
+
+// record counts a finished request that took the given number of attempts.
+func (s *clientStats) record(attempts int, failed bool) {
+	s.requests.Add(1)
+
+	if failed {
+		s.failures.Add(1)
+	} else {
+		s.successes.Add(1)
+	}
+
+	if attempts > 1 {
+		s.retries.Add(uint64(attempts - 1))
+	}
+
+	counter, _ := s.attempts.LoadOrStore(attempts, new(atomic.Uint64))
+	counter.(*atomic.Uint64).Add(1)
+}
+
+// snapshot returns the current values of the counters.
+func (s *clientStats) snapshot() ClientStats {
+	stats := ClientStats{
+		Requests:      s.requests.Load(),
+		Retries:       s.retries.Load(),
+		Successes:     s.successes.Load(),
+		Failures:      s.failures.Load(),
+		AttemptCounts: make(map[int]uint64),
+	}
+
+	s.attempts.Range(func(key, value any) bool {
+		stats.AttemptCounts[key.(int)] = value.(*atomic.Uint64).Load()
+		return true
+	})
+
+	return stats
+}
+
+// attemptCounterKey is the context key of the attempt counter of a request.
+type attemptCounterKey struct{}
+
+// withAttemptCounter returns a shallow copy of req whose context carries a counter
+// incremented by the retry transport on every attempt.
+func withAttemptCounter(req *http.Request) (*http.Request, *atomic.Int64) {
+	counter := new(atomic.Int64)
+
+	return req.WithContext(context.WithValue(req.Context(), attemptCounterKey{}, counter)), counter
+}
+
+// countAttempt increments the attempt counter carried by ctx, if any.
+func countAttempt(ctx context.Context) {
+	if counter, ok := ctx.Value(attemptCounterKey{}).(*atomic.Int64); ok {
+		counter.Add(1)
+	}
+}
@@ -0,0 +1,129 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGenericClient_Stats(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			// Fail two out of every three attempts
+			if calls.Add(1)%3 != 0 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	client := NewGenericClient[User](
+		WithMaxRetries[User](3),
+		WithRetryStrategyFunc[User](func(int) time.Duration { return time.Millisecond }),
+	)
+
+	assertEqual(t, ClientStats{AttemptCounts: map[int]uint64{}}, client.Stats())
+
+	if _, err := client.Get(server.URL + "/ok"); err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if _, err := client.Get(server.URL + "/flaky"); err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if _, err := client.Get(server.URL + "/missing"); err == nil {
+		t.Fatal("Expected an error for 404")
+	}
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/ok", nil)
+	if resp, err := client.ExecuteRaw(req); err != nil {
+		t.Fatalf("ExecuteRaw() failed: %v", err)
+	} else {
+		resp.Body.Close()
+	}
+
+	assertEqual(t, ClientStats{
+		Requests:      4,
+		Retries:       2,
+		Successes:     3,
+		Failures:      1,
+		AttemptCounts: map[int]uint64{1: 3, 3: 1},
+	}, client.Stats())
+
+	t.Run("Concurrent requests", func(t *testing.T) {
+		client := NewGenericClient[User]()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = client.Get(server.URL + "/ok")
+				_ = client.Stats()
+			}()
+		}
+		wg.Wait()
+
+		stats := client.Stats()
+		assertEqual(t, uint64(20), stats.Requests)
+		assertEqual(t, uint64(20), stats.Successes)
+		assertEqual(t, uint64(20), stats.AttemptCounts[1])
+	})
+
+	t.Run("Error statuses of raw requests are failures", func(t *testing.T) {
+		var hookErrs []error
+		client := NewGenericClient[User](WithAfterResponse[User](func(_ *http.Request, _ int, _ time.Duration, err error) {
+			hookErrs = append(hookErrs, err)
+		}))
+
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/missing", nil)
+		if resp, err := client.ExecuteRaw(req); err != nil {
+			t.Fatalf("ExecuteRaw() failed: %v", err)
+		} else {
+			resp.Body.Close()
+		}
+
+		if _, err := client.GetPage(server.URL + "/missing"); err == nil {
+			t.Fatal("Expected an error for 404")
+		}
+		if _, err := client.GetPage(server.URL + "/ok"); err == nil {
+			t.Fatal("Expected an error for a body without items")
+		}
+		if err := client.DownloadVerified(server.URL+"/ok", filepath.Join(t.TempDir(), "user.json"), strings.Repeat("0", 64)); !errors.Is(err, ErrChecksumMismatch) {
+			t.Fatalf("Expected ErrChecksumMismatch, got %v", err)
+		}
+
+		stats := client.Stats()
+		assertEqual(t, uint64(4), stats.Failures)
+		assertEqual(t, uint64(0), stats.Successes)
+
+		// Hooks get the final error of the helpers
+		assertEqual(t, 4, len(hookErrs))
+		assertTrue(t, hookErrs[0] == nil)
+		for _, err := range hookErrs[1:] {
+			assertTrue(t, err != nil)
+		}
+	})
+
+	t.Run("Requests not sent through the retry layer", func(t *testing.T) {
+		client := NewGenericClient[User](WithHTTPClient[User](&capturingClient{statusCode: http.StatusOK, body: `{"id":1}`}))
+
+		if _, err := client.Get("https://api.example.com/users/1"); err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+
+		assertEqual(t, map[int]uint64{0: 1}, client.Stats().AttemptCounts)
+	})
+}
@@ -0,0 +1,340 @@
+// Package httpxtest provides utilities for testing code that uses the httpx package.
+//
+// MockTransport is an http.RoundTripper that answers requests with canned responses
+// registered per method and URL pattern, and records every call for later assertions.
+// Plug it into a generic client with httpx.WithTransport, or into any *http.Client:
+//
+//	mock := httpxtest.NewMockTransport()
+//	mock.On("GET", "/users/1").Return(200, `{"id":1,"name":"Ada"}`)
+//
+//	client := httpx.NewGenericClient[User](httpx.WithTransport[User](mock))
+//	resp, err := client.Get("https://api.example.com/users/1")
+//
+//	mock.AssertCalled(t, "GET", "/users/1")
+package httpxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ErrNoMatchingRoute is returned by MockTransport.RoundTrip when no registered route
+// matches the request.
+var ErrNoMatchingRoute = errors.New("httpxtest: no matching route")
+
+// Call is a request received by a MockTransport.
+type Call struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// Route is a canned response registered on a MockTransport with On. Its setters are safe
+// to call while the transport serves requests.
+type Route struct {
+	mu      *sync.Mutex // Mutex of the MockTransport, guarding the fields below
+	method  string
+	pattern string
+
+	status int
+	body   []byte
+	header http.Header
+	err    error
+
+	times int // Maximum number of requests answered (0 = unlimited)
+	calls int
+}
+
+// MockTransport is an http.RoundTripper that serves registered canned responses.
+// Routes are matched in registration order; the first route that matches the request
+// and has not used up its Times limit answers it. It is safe for concurrent use.
+type MockTransport struct {
+	mu     sync.Mutex
+	routes []*Route
+	calls  []Call
+}
+
+// NewMockTransport creates an empty MockTransport.
+func NewMockTransport() *MockTransport {
+	return &MockTransport{}
+}
+
+// On registers a route for requests with the given method and URL pattern and returns it
+// so its response can be set with Return or ReturnError.
+//
+// An empty method or "*" matches any method. The pattern is matched with path.Match against
+// the request path, or against the full URL without query string ("https://host/path")
+// when it contains "://". A route without a response answers 200 with an empty body.
+func (m *MockTransport) On(method, pattern string) *Route {
+	route := &Route{
+		mu:      &m.mu,
+		method:  strings.ToUpper(method),
+		pattern: pattern,
+		status:  http.StatusOK,
+		header:  make(http.Header),
+	}
+
+	m.mu.Lock()
+	m.routes = append(m.routes, route)
+	m.mu.Unlock()
+
+	return route
+}
+
+// Return sets the status code and body of the route's response and returns the Route
+// for method chaining. The body may be a string, a []byte, nil, or any other value,
+// which is encoded as JSON.
+func (r *Route) Return(status int, body any) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status = status
+	r.err = nil
+
+	switch b := body.(type) {
+	case nil:
+		r.body = nil
+	case string:
+		r.body = []byte(b)
+	case []byte:
+		r.body = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			r.err = fmt.Errorf("httpxtest: marshal response body: %w", err)
+			return r
+		}
+		r.body = data
+		if r.header.Get("Content-Type") == "" {
+			r.header.Set("Content-Type", "application/json")
+		}
+	}
+
+	return r
+}
+
+// ReturnError makes the route fail requests with err instead of returning a response,
+// e.g. to simulate network errors, and returns the Route for method chaining.
+func (r *Route) ReturnError(err error) *Route {
+	r.mu.Lock()
+	r.err = err
+	r.mu.Unlock()
+
+	return r
+}
+
+// WithHeader sets a header on the route's response and returns the Route for method chaining.
+func (r *Route) WithHeader(key, value string) *Route {
+	r.mu.Lock()
+	r.header.Set(key, value)
+	r.mu.Unlock()
+
+	return r
+}
+
+// Times limits the route to answering n requests, after which later matching routes are
+// used, and returns the Route for method chaining. This allows sequences of responses,
+// e.g. a failure followed by a success to exercise retries.
+func (r *Route) Times(n int) *Route {
+	r.mu.Lock()
+	r.times = n
+	r.mu.Unlock()
+
+	return r
+}
+
+// matches reports whether the route matches the given method and URL.
+func (r *Route) matches(method, target, urlPath string) bool {
+	if r.method != "" && r.method != "*" && r.method != method {
+		return false
+	}
+
+	subject := urlPath
+	if strings.Contains(r.pattern, "://") {
+		subject = target
+	}
+
+	ok, err := path.Match(r.pattern, subject)
+	return err == nil && ok
+}
+
+// RoundTrip implements http.RoundTripper. It records the request and answers it with the
+// first matching route, or returns an error wrapping ErrNoMatchingRoute.
+func (m *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("httpxtest: read request body: %w", err)
+		}
+		body = data
+	}
+
+	target := urlWithoutQuery(req)
+
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	})
+
+	var route *Route
+	for _, r := range m.routes {
+		if r.times > 0 && r.calls >= r.times {
+			continue
+		}
+		if r.matches(req.Method, target, req.URL.Path) {
+			route = r
+			break
+		}
+	}
+
+	if route == nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("%w for %s %s", ErrNoMatchingRoute, req.Method, req.URL)
+	}
+
+	route.calls++
+	status, respBody, header, err := route.status, route.body, route.header.Clone(), route.err
+	m.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// Calls returns a copy of all requests received, in order.
+func (m *MockTransport) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// CallCount returns the number of received requests matching the method and URL pattern,
+// using the same matching rules as On.
+func (m *MockTransport) CallCount(method, pattern string) int {
+	matcher := &Route{method: strings.ToUpper(method), pattern: pattern}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for _, call := range m.calls {
+		req, err := http.NewRequest(call.Method, call.URL, nil)
+		if err != nil {
+			continue
+		}
+		if matcher.matches(call.Method, urlWithoutQuery(req), req.URL.Path) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// AssertCalled fails the test if no request matching the method and URL pattern was received.
+func (m *MockTransport) AssertCalled(t testing.TB, method, pattern string) bool {
+	t.Helper()
+
+	if m.CallCount(method, pattern) == 0 {
+		t.Errorf("httpxtest: expected a call to %s %s, got none (calls: %s)", method, pattern, m.describeCalls())
+		return false
+	}
+
+	return true
+}
+
+// AssertNotCalled fails the test if a request matching the method and URL pattern was received.
+func (m *MockTransport) AssertNotCalled(t testing.TB, method, pattern string) bool {
+	t.Helper()
+
+	if n := m.CallCount(method, pattern); n > 0 {
+		t.Errorf("httpxtest: expected no calls to %s %s, got %d", method, pattern, n)
+		return false
+	}
+
+	return true
+}
+
+// AssertNumberOfCalls fails the test if the number of requests matching the method and
+// URL pattern is not n.
+func (m *MockTransport) AssertNumberOfCalls(t testing.TB, method, pattern string, n int) bool {
+	t.Helper()
+
+	if got := m.CallCount(method, pattern); got != n {
+		t.Errorf("httpxtest: expected %d calls to %s %s, got %d", n, method, pattern, got)
+		return false
+	}
+
+	return true
+}
+
+// AssertExpectations fails the test if any registered route was never used.
+func (m *MockTransport) AssertExpectations(t testing.TB) bool {
+	t.Helper()
+
+	m.mu.Lock()
+	var unused []string
+	for _, r := range m.routes {
+		if r.calls == 0 {
+			unused = append(unused, fmt.Sprintf("%s %s", r.method, r.pattern))
+		}
+	}
+	m.mu.Unlock()
+
+	if len(unused) > 0 {
+		t.Errorf("httpxtest: routes never called: %s", strings.Join(unused, ", "))
+		return false
+	}
+
+	return true
+}
+
+// describeCalls formats the received requests for failure messages.
+func (m *MockTransport) describeCalls() string {
+	calls := m.Calls()
+	if len(calls) == 0 {
+		return "none"
+	}
+
+	parts := make([]string, len(calls))
+	for i, call := range calls {
+		parts[i] = call.Method + " " + call.URL
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// urlWithoutQuery returns the request URL as scheme://host/path.
+func urlWithoutQuery(req *http.Request) string {
+	u := *req.URL
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return u.String()
+}
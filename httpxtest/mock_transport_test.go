@@ -0,0 +1,160 @@
+package httpxtest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slashdevops/httpx"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// recordingTB captures assertion failures instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMockTransport_GenericClient(t *testing.T) {
+	mock := NewMockTransport()
+	mock.On("GET", "/users/1").Return(http.StatusOK, `{"id":1,"name":"Ada"}`)
+	mock.On("POST", "/users").Return(http.StatusCreated, user{ID: 2, Name: "Grace"}).WithHeader("Location", "/users/2")
+	mock.On("GET", "/users/*").Return(http.StatusNotFound, `{"message":"not found"}`)
+
+	client := httpx.NewGenericClient[user](httpx.WithTransport[user](mock))
+
+	resp, err := client.Get("https://api.example.com/users/1")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if resp.Data.Name != "Ada" {
+		t.Errorf("Data.Name = %q, want %q", resp.Data.Name, "Ada")
+	}
+
+	resp, err = client.Post("https://api.example.com/users", strings.NewReader(`{"name":"Grace"}`))
+	if err != nil {
+		t.Fatalf("Post() failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated || resp.Data.ID != 2 {
+		t.Errorf("Post() = %d %+v, want 201 with ID 2", resp.StatusCode, resp.Data)
+	}
+	if got := resp.Headers.Get("Location"); got != "/users/2" {
+		t.Errorf("Location = %q, want /users/2", got)
+	}
+
+	_, err = client.Get("https://api.example.com/users/42")
+	var apiErr *httpx.ErrorResponse
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 ErrorResponse, got %v", err)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("Calls() = %d, want 3", len(calls))
+	}
+	if string(calls[1].Body) != `{"name":"Grace"}` {
+		t.Errorf("Recorded body = %q", calls[1].Body)
+	}
+
+	mock.AssertCalled(t, "GET", "/users/1")
+	mock.AssertNumberOfCalls(t, "GET", "/users/*", 2)
+	mock.AssertNotCalled(t, "DELETE", "/users/*")
+	mock.AssertExpectations(t)
+}
+
+func TestMockTransport_Sequences(t *testing.T) {
+	mock := NewMockTransport()
+	mock.On("GET", "https://api.example.com/flaky").Return(http.StatusServiceUnavailable, nil).Times(1)
+	mock.On("GET", "https://api.example.com/flaky").Return(http.StatusOK, `{"id":7}`)
+	mock.On("GET", "/broken").ReturnError(errors.New("connection refused"))
+
+	client := &http.Client{Transport: mock}
+
+	for i, want := range []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK} {
+		resp, err := client.Get("https://api.example.com/flaky?attempt=1")
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("request %d status = %d, want %d", i, resp.StatusCode, want)
+		}
+	}
+
+	if _, err := client.Get("https://api.example.com/broken"); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("Expected route error, got %v", err)
+	}
+
+	if _, err := client.Get("https://api.example.com/unknown"); !errors.Is(err, ErrNoMatchingRoute) {
+		t.Errorf("Expected ErrNoMatchingRoute, got %v", err)
+	}
+
+	retrying := httpx.NewGenericClient[user](
+		httpx.WithTransport[user](mock),
+		httpx.WithRetryStrategyFunc[user](func(int) time.Duration { return time.Millisecond }),
+	)
+	mock.On("GET", "/retry").Return(http.StatusBadGateway, nil).Times(2)
+	mock.On("GET", "/retry").Return(http.StatusOK, `{"id":9}`)
+	if resp, err := retrying.Get("https://api.example.com/retry"); err != nil || resp.Data.ID != 9 {
+		t.Fatalf("Get() = %v, %v; want ID 9", resp, err)
+	}
+	mock.AssertNumberOfCalls(t, "GET", "/retry", 3)
+
+	rec := &recordingTB{TB: t}
+	mock.AssertCalled(rec, "PUT", "/flaky")
+	mock.AssertNotCalled(rec, "GET", "/flaky")
+	mock.AssertNumberOfCalls(rec, "*", "/flaky", 1)
+	if len(rec.errors) != 3 {
+		t.Errorf("Expected 3 assertion failures, got %d: %v", len(rec.errors), rec.errors)
+	}
+
+	unused := NewMockTransport()
+	unused.On("DELETE", "/users/1")
+	rec = &recordingTB{TB: t}
+	if unused.AssertExpectations(rec) || len(rec.errors) != 1 {
+		t.Errorf("Expected AssertExpectations to report the unused route, got %v", rec.errors)
+	}
+}
+
+func TestMockTransport_ConcurrentRouteSetup(t *testing.T) {
+	mock := NewMockTransport()
+	route := mock.On("GET", "/users/1").Return(http.StatusOK, `{"id":1}`)
+	client := &http.Client{Transport: mock}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 50 {
+			resp, err := client.Get("https://api.example.com/users/1")
+			if err != nil {
+				continue
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+	}()
+
+	// Routes can be reconfigured while requests are served
+	for i := range 50 {
+		route.Return(http.StatusOK, fmt.Sprintf(`{"id":%d}`, i)).WithHeader("X-Version", fmt.Sprint(i)).Times(0)
+	}
+	route.ReturnError(errors.New("connection refused"))
+	<-done
+
+	mock.AssertNumberOfCalls(t, "GET", "/users/1", 50)
+}
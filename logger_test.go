@@ -218,3 +218,44 @@ func TestGenericClient_WithLogger(t *testing.T) {
 		t.Errorf("Expected retry log from GenericClient, got: %s", logOutput)
 	}
 }
+
+// TestRetryTransport_LoggerDelayDetails verifies that retry logs explain how the delay was chosen
+func TestRetryTransport_LoggerDelayDetails(t *testing.T) {
+	attempts := atomic.Int32{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logBuf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logBuf, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+	}))
+
+	client := NewHTTPRetryClient(
+		WithMaxRetriesRetry(1),
+		WithRetryStrategyRetry(FixedDelay(10*time.Millisecond)),
+		WithRespectRetryAfterRetry(true),
+		WithRetryMaxDelayRetry(20*time.Millisecond),
+		WithLoggerRetry(logger),
+	)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	logOutput := logBuf.String()
+	for _, expected := range []string{"delay_source=capped", "computed_delay=1h0m0s", "delay=20ms"} {
+		if !strings.Contains(logOutput, expected) {
+			t.Errorf("Expected %q in log, got: %s", expected, logOutput)
+		}
+	}
+}